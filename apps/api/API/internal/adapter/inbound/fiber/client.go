@@ -10,6 +10,7 @@ import (
 	"prabogo/internal/model"
 	inbound_port "prabogo/internal/port/inbound"
 	"prabogo/utils/activity"
+	"prabogo/utils/log"
 )
 
 type clientAdapter struct {
@@ -26,22 +27,17 @@ func NewClientAdapter(
 
 func (h *clientAdapter) Upsert(a any) error {
 	c := a.(*fiber.Ctx)
-	ctx := activity.NewContext("http_client_upsert")
+	ctx := requestContext(c, "http_client_upsert")
 	var payload []model.ClientInput
 	if err := c.BodyParser(&payload); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.Response{
-			Success: false,
-			Error:   err.Error(),
-		})
+		return errorResponse(c, stacktrace.PropagateWithCode(err, model.ErrorCodeValidation, "invalid body"))
 	}
 	ctx = context.WithValue(ctx, activity.Payload, payload)
 
 	results, err := h.domain.Client().Upsert(ctx, payload)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(model.Response{
-			Success: false,
-			Error:   stacktrace.RootCause(err).Error(),
-		})
+		log.WithContext(ctx).Errorf("client upsert error %s", err.Error())
+		return errorResponse(c, err)
 	}
 
 	return c.JSON(model.Response{
@@ -52,48 +48,105 @@ func (h *clientAdapter) Upsert(a any) error {
 
 func (h *clientAdapter) Find(a any) error {
 	c := a.(*fiber.Ctx)
-	ctx := activity.NewContext("http_client_find_by_filter")
+	ctx := requestContext(c, "http_client_find_by_filter")
 	var payload model.ClientFilter
 	if err := c.BodyParser(&payload); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.Response{
-			Success: false,
-			Error:   err.Error(),
-		})
+		return errorResponse(c, stacktrace.PropagateWithCode(err, model.ErrorCodeValidation, "invalid body"))
 	}
 	ctx = context.WithValue(ctx, activity.Payload, payload)
 
-	results, err := h.domain.Client().FindByFilter(ctx, payload)
+	results, page, err := h.domain.Client().FindByFilter(ctx, payload)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(model.Response{
-			Success: false,
-			Error:   stacktrace.RootCause(err).Error(),
-		})
+		log.WithContext(ctx).Errorf("client find by filter error %s", err.Error())
+		return errorResponse(c, err)
 	}
 
-	return c.JSON(model.Response{
+	response := model.Response{
 		Success: true,
 		Data:    results,
-	})
+	}
+	if page != nil {
+		response.Meta = &model.Meta{Page: page}
+	}
+
+	return c.JSON(response)
 }
 
 func (h *clientAdapter) Delete(a any) error {
 	c := a.(*fiber.Ctx)
-	ctx := activity.NewContext("http_client_delete_by_filter")
+	ctx := requestContext(c, "http_client_delete_by_filter")
 	var payload model.ClientFilter
 	if err := c.BodyParser(&payload); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(model.Response{
-			Success: false,
-			Error:   err.Error(),
-		})
+		return errorResponse(c, stacktrace.PropagateWithCode(err, model.ErrorCodeValidation, "invalid body"))
 	}
 	ctx = context.WithValue(ctx, activity.Payload, payload)
 
 	err := h.domain.Client().DeleteByFilter(ctx, payload)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(model.Response{
-			Success: false,
-			Error:   stacktrace.RootCause(err).Error(),
-		})
+		log.WithContext(ctx).Errorf("client delete by filter error %s", err.Error())
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(model.Response{
+		Success: true,
+	})
+}
+
+func (h *clientAdapter) UpsertKey(a any) error {
+	c := a.(*fiber.Ctx)
+	ctx := requestContext(c, "http_client_key_upsert")
+	var payload model.ClientKeyInput
+	if err := c.BodyParser(&payload); err != nil {
+		return errorResponse(c, stacktrace.PropagateWithCode(err, model.ErrorCodeValidation, "invalid body"))
+	}
+	ctx = context.WithValue(ctx, activity.Payload, payload)
+
+	result, err := h.domain.Client().UpsertKey(ctx, payload)
+	if err != nil {
+		log.WithContext(ctx).Errorf("client key upsert error %s", err.Error())
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(model.Response{
+		Success: true,
+		Data:    result,
+	})
+}
+
+func (h *clientAdapter) FindKeys(a any) error {
+	c := a.(*fiber.Ctx)
+	ctx := requestContext(c, "http_client_key_find_by_filter")
+	var payload model.ClientKeyFilter
+	if err := c.BodyParser(&payload); err != nil {
+		return errorResponse(c, stacktrace.PropagateWithCode(err, model.ErrorCodeValidation, "invalid body"))
+	}
+	ctx = context.WithValue(ctx, activity.Payload, payload)
+
+	results, err := h.domain.Client().FindKeysByFilter(ctx, payload)
+	if err != nil {
+		log.WithContext(ctx).Errorf("client key find by filter error %s", err.Error())
+		return errorResponse(c, err)
+	}
+
+	return c.JSON(model.Response{
+		Success: true,
+		Data:    results,
+	})
+}
+
+func (h *clientAdapter) DeleteKeys(a any) error {
+	c := a.(*fiber.Ctx)
+	ctx := requestContext(c, "http_client_key_delete_by_filter")
+	var payload model.ClientKeyFilter
+	if err := c.BodyParser(&payload); err != nil {
+		return errorResponse(c, stacktrace.PropagateWithCode(err, model.ErrorCodeValidation, "invalid body"))
+	}
+	ctx = context.WithValue(ctx, activity.Payload, payload)
+
+	err := h.domain.Client().DeleteKeysByFilter(ctx, payload)
+	if err != nil {
+		log.WithContext(ctx).Errorf("client key delete by filter error %s", err.Error())
+		return errorResponse(c, err)
 	}
 
 	return c.JSON(model.Response{
@@ -2,6 +2,7 @@ package fiber_inbound_adapter_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -15,9 +16,12 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 
 	fiber_inbound_adapter "prabogo/internal/adapter/inbound/fiber"
+	"prabogo/internal/config"
 	"prabogo/internal/domain"
 	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
 	mock_outbound_port "prabogo/tests/mocks/port"
+	"prabogo/utils/validate"
 )
 
 func TestClientAdapter(t *testing.T) {
@@ -33,14 +37,20 @@ func TestClientAdapter(t *testing.T) {
 		mockClientDatabasePort := mock_outbound_port.NewMockClientDatabasePort(mockCtrl)
 		mockClientCachePort := mock_outbound_port.NewMockClientCachePort(mockCtrl)
 		mockClientWorkflowPort := mock_outbound_port.NewMockClientWorkflowPort(mockCtrl)
+		mockClientKeyDatabasePort := mock_outbound_port.NewMockClientKeyDatabasePort(mockCtrl)
 
 		mockDatabasePort.EXPECT().Client().Return(mockClientDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().ClientKey().Return(mockClientKeyDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, txFunc outbound_port.InTransaction) (interface{}, error) {
+				return txFunc(mockDatabasePort)
+			}).AnyTimes()
 		mockMessagePort.EXPECT().Client().Return(mock_outbound_port.NewMockClientMessagePort(mockCtrl)).AnyTimes()
 		mockCachePort.EXPECT().Client().Return(mockClientCachePort).AnyTimes()
 		mockWorkflowPort.EXPECT().Client().Return(mockClientWorkflowPort).AnyTimes()
 
 		dom := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockWorkflowPort)
-		adapter := fiber_inbound_adapter.NewAdapter(dom)
+		adapter := fiber_inbound_adapter.NewAdapter(dom, config.Http{})
 
 		app := fiber.New()
 		app.Post("/client-upsert", func(c *fiber.Ctx) error {
@@ -62,7 +72,6 @@ func TestClientAdapter(t *testing.T) {
 				ID: 1,
 				ClientInput: model.ClientInput{
 					Name:      "Test Client",
-					BearerKey: "test-bearer-key",
 					CreatedAt: time.Now(),
 					UpdatedAt: time.Now(),
 				},
@@ -75,8 +84,11 @@ func TestClientAdapter(t *testing.T) {
 
 		Convey("Upsert", func() {
 			Convey("Success", func() {
-				mockClientDatabasePort.EXPECT().Upsert(gomock.Any()).Return(nil).Times(1)
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteByClientIDs(gomock.Any(), []int{1}).Return(nil).Times(1)
 
 				body, _ := json.Marshal(inputs)
 				req := httptest.NewRequest(http.MethodPost, "/client-upsert", bytes.NewReader(body))
@@ -103,8 +115,27 @@ func TestClientAdapter(t *testing.T) {
 				So(resp.StatusCode, ShouldEqual, http.StatusBadRequest)
 			})
 
+			Convey("Invalid input", func() {
+				body, _ := json.Marshal([]model.ClientInput{{Name: " "}})
+				req := httptest.NewRequest(http.MethodPost, "/client-upsert", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+
+				resp, err := app.Test(req)
+				So(err, ShouldBeNil)
+				defer resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusBadRequest)
+
+				respBody, _ := io.ReadAll(resp.Body)
+				var result model.Response
+				json.Unmarshal(respBody, &result)
+				So(result.Code, ShouldEqual, "validation_failed")
+				So(result.Details, ShouldResemble, []validate.FieldError{
+					{Field: "[0].name", Rule: "required", Message: "is required"},
+				})
+			})
+
 			Convey("Domain error", func() {
-				mockClientDatabasePort.EXPECT().Upsert(gomock.Any()).Return(errors.New("database error")).Times(1)
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(errors.New("database error")).Times(1)
 
 				body, _ := json.Marshal(inputs)
 				req := httptest.NewRequest(http.MethodPost, "/client-upsert", bytes.NewReader(body))
@@ -119,7 +150,7 @@ func TestClientAdapter(t *testing.T) {
 
 		Convey("Find", func() {
 			Convey("Success", func() {
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
 
 				body, _ := json.Marshal(filter)
 				req := httptest.NewRequest(http.MethodPost, "/client-find", bytes.NewReader(body))
@@ -136,6 +167,28 @@ func TestClientAdapter(t *testing.T) {
 				So(result.Success, ShouldBeTrue)
 			})
 
+			Convey("Paged", func() {
+				mockClientDatabasePort.EXPECT().CountByFilter(gomock.Any(), gomock.Any()).Return(5, nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+
+				body := []byte(`{"name_search": "test", "sort": [{"field": "name", "direction": "desc"}], "page": {"limit": 10}}`)
+				req := httptest.NewRequest(http.MethodPost, "/client-find", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+
+				resp, err := app.Test(req)
+				So(err, ShouldBeNil)
+				defer resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+
+				respBody, _ := io.ReadAll(resp.Body)
+				var result model.Response
+				json.Unmarshal(respBody, &result)
+				So(result.Meta, ShouldNotBeNil)
+				So(result.Meta.Page.Total, ShouldEqual, 5)
+				So(result.Meta.Page.Limit, ShouldEqual, 10)
+				So(result.Meta.Page.NextCursor, ShouldBeEmpty)
+			})
+
 			Convey("Invalid JSON", func() {
 				req := httptest.NewRequest(http.MethodPost, "/client-find", bytes.NewReader([]byte("invalid")))
 				req.Header.Set("Content-Type", "application/json")
@@ -147,7 +200,7 @@ func TestClientAdapter(t *testing.T) {
 			})
 
 			Convey("Domain error", func() {
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("pq: relation does not exist")).Times(1)
 
 				body, _ := json.Marshal(filter)
 				req := httptest.NewRequest(http.MethodPost, "/client-find", bytes.NewReader(body))
@@ -157,12 +210,54 @@ func TestClientAdapter(t *testing.T) {
 				So(err, ShouldBeNil)
 				defer resp.Body.Close()
 				So(resp.StatusCode, ShouldEqual, http.StatusInternalServerError)
+
+				respBody, _ := io.ReadAll(resp.Body)
+				var result model.Response
+				json.Unmarshal(respBody, &result)
+				So(result.Code, ShouldEqual, "internal")
+				So(result.Error, ShouldEqual, "internal error")
+			})
+
+			Convey("Empty filter", func() {
+				req := httptest.NewRequest(http.MethodPost, "/client-find", bytes.NewReader([]byte(`{}`)))
+				req.Header.Set("Content-Type", "application/json")
+
+				resp, err := app.Test(req)
+				So(err, ShouldBeNil)
+				defer resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusBadRequest)
+
+				respBody, _ := io.ReadAll(resp.Body)
+				var result model.Response
+				json.Unmarshal(respBody, &result)
+				So(result.Code, ShouldEqual, "validation_failed")
+				So(result.Error, ShouldEqual, "filter is empty")
+			})
+
+			Convey("Database unavailable", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, context.DeadlineExceeded).Times(1)
+
+				body, _ := json.Marshal(filter)
+				req := httptest.NewRequest(http.MethodPost, "/client-find", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+
+				resp, err := app.Test(req)
+				So(err, ShouldBeNil)
+				defer resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusServiceUnavailable)
+
+				respBody, _ := io.ReadAll(resp.Body)
+				var result model.Response
+				json.Unmarshal(respBody, &result)
+				So(result.Code, ShouldEqual, "unavailable")
 			})
 		})
 
 		Convey("Delete", func() {
 			Convey("Success", func() {
-				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteByClientIDs(gomock.Any(), []int{1}).Return(nil).Times(1)
 
 				body, _ := json.Marshal(filter)
 				req := httptest.NewRequest(http.MethodPost, "/client-delete", bytes.NewReader(body))
@@ -190,7 +285,8 @@ func TestClientAdapter(t *testing.T) {
 			})
 
 			Convey("Domain error", func() {
-				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any()).Return(errors.New("error")).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any(), gomock.Any()).Return(errors.New("error")).Times(1)
 
 				body, _ := json.Marshal(filter)
 				req := httptest.NewRequest(http.MethodPost, "/client-delete", bytes.NewReader(body))
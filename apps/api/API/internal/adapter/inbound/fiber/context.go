@@ -0,0 +1,31 @@
+package fiber_inbound_adapter
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"prabogo/utils/activity"
+)
+
+// requestContext returns the activity context of the request. Every call for the same
+// request shares one transaction ID and carries the client ID and roles resolved by the middleware.
+func requestContext(c *fiber.Ctx, action string) context.Context {
+	trxID, ok := c.Locals(LocalsTransactionID).(string)
+	if !ok || trxID == "" {
+		trxID = uuid.New().String()
+		c.Locals(LocalsTransactionID, trxID)
+	}
+
+	ctx := activity.WithTransactionID(c.UserContext(), trxID)
+	ctx = activity.WithAction(ctx, action)
+	if clientID, ok := c.Locals(LocalsClientID).(string); ok && clientID != "" {
+		ctx = activity.WithClientID(ctx, clientID)
+	}
+	if roles, ok := c.Locals(LocalsRoles).([]string); ok {
+		ctx = activity.WithRoles(ctx, roles)
+	}
+
+	return ctx
+}
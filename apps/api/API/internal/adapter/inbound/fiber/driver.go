@@ -0,0 +1,46 @@
+package fiber_inbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+
+	"prabogo/internal/config"
+	"prabogo/internal/domain"
+	"prabogo/internal/driver"
+	"prabogo/utils/deadline"
+	"prabogo/utils/lifecycle"
+)
+
+func init() {
+	driver.RegisterInbound(driver.InboundHttp, driver.New("fiber", "HTTP server on Fiber", func(settings config.Http) driver.InboundFactory {
+		return func(ctx context.Context, domain domain.Domain, manager *lifecycle.Manager, args []string) error {
+			return serve(ctx, settings, domain, manager.Timeout())
+		}
+	}))
+}
+
+// serve listens until ctx is done, then stops accepting connections and returns once
+// in-flight requests finish or shutdownTimeout passes
+func serve(ctx context.Context, settings config.Http, domain domain.Domain, shutdownTimeout time.Duration) error {
+	app := fiber.New()
+	// A panicking handler fails its request instead of the process
+	app.Use(recover.New())
+	InitRoute(ctx, app, NewAdapter(domain, settings))
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- app.Listen(":" + settings.Port)
+	}()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := deadline.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+		defer cancel()
+		return app.ShutdownWithContext(shutdownCtx)
+	case err := <-errs:
+		return err
+	}
+}
@@ -0,0 +1,46 @@
+package fiber_inbound_adapter
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
+
+	"prabogo/internal/model"
+	"prabogo/utils/validate"
+)
+
+// errorStatuses maps error codes to HTTP statuses, errors without a code are a 500
+var errorStatuses = map[stacktrace.ErrorCode]int{
+	model.ErrorCodeValidation:   fiber.StatusBadRequest,
+	model.ErrorCodeNotFound:     fiber.StatusNotFound,
+	model.ErrorCodeConflict:     fiber.StatusConflict,
+	model.ErrorCodeUnauthorized: fiber.StatusUnauthorized,
+	model.ErrorCodeForbidden:    fiber.StatusForbidden,
+	model.ErrorCodeUnavailable:  fiber.StatusServiceUnavailable,
+}
+
+// errorStatus maps an error to its HTTP status
+func errorStatus(err error) int {
+	if status, ok := errorStatuses[model.ErrorCodeOf(err)]; ok {
+		return status
+	}
+	return fiber.StatusInternalServerError
+}
+
+// errorResponse writes err with its status, code and a message safe to show to the caller.
+// Validation errors also list their invalid fields.
+func errorResponse(c *fiber.Ctx, err error) error {
+	response := model.Response{
+		Success: false,
+		Code:    model.ErrorName(err),
+		Error:   model.ErrorMessage(err),
+	}
+
+	var fieldErrors validate.Errors
+	if errors.As(stacktrace.RootCause(err), &fieldErrors) {
+		response.Details = fieldErrors
+	}
+
+	return c.Status(errorStatus(err)).JSON(response)
+}
@@ -0,0 +1,51 @@
+package fiber_inbound_adapter
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	inbound_port "prabogo/internal/port/inbound"
+	"prabogo/utils/log"
+)
+
+type healthAdapter struct {
+	domain domain.Domain
+}
+
+func NewHealthAdapter(
+	domain domain.Domain,
+) inbound_port.HealthHttpPort {
+	return &healthAdapter{
+		domain: domain,
+	}
+}
+
+func (h *healthAdapter) Live(a any) error {
+	c := a.(*fiber.Ctx)
+	return c.JSON(model.Health{Status: model.HealthStatusUp, CheckedAt: time.Now()})
+}
+
+func (h *healthAdapter) Ready(a any) error {
+	c := a.(*fiber.Ctx)
+	ctx := c.UserContext()
+
+	health := h.domain.Health().Ready(ctx)
+	if !health.IsUp() {
+		for name, component := range health.Components {
+			if component.Status != model.HealthStatusUp {
+				log.WithContext(ctx).Warnf("readiness check of %s failed: %s", name, component.Error)
+			}
+		}
+		for name, inbound := range health.Inbounds {
+			if inbound.Status != model.HealthStatusUp {
+				log.WithContext(ctx).Warnf("%s inbound is down: %s", name, inbound.Error)
+			}
+		}
+		return c.Status(fiber.StatusServiceUnavailable).JSON(health)
+	}
+
+	return c.JSON(health)
+}
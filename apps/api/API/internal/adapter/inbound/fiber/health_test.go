@@ -0,0 +1,75 @@
+package fiber_inbound_adapter_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	fiber_inbound_adapter "prabogo/internal/adapter/inbound/fiber"
+	"prabogo/internal/config"
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	mock_outbound_port "prabogo/tests/mocks/port"
+)
+
+func TestHealthAdapter(t *testing.T) {
+	Convey("Test Health HTTP Adapter", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockWorkflowPort := mock_outbound_port.NewMockWorkflowPort(mockCtrl)
+
+		mockMessagePort.EXPECT().Ping(gomock.Any()).Return(nil).AnyTimes()
+		mockCachePort.EXPECT().Ping(gomock.Any()).Return(nil).AnyTimes()
+		mockWorkflowPort.EXPECT().Ping(gomock.Any()).Return(nil).AnyTimes()
+
+		dom := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockWorkflowPort)
+		app := fiber.New()
+		fiber_inbound_adapter.InitRoute(context.Background(), app, fiber_inbound_adapter.NewAdapter(dom, config.Http{}))
+
+		get := func(path string) (int, model.Health) {
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+			So(err, ShouldBeNil)
+			defer resp.Body.Close()
+
+			body, _ := io.ReadAll(resp.Body)
+			var result model.Health
+			So(json.Unmarshal(body, &result), ShouldBeNil)
+			return resp.StatusCode, result
+		}
+
+		Convey("Liveness needs no authentication or dependencies", func() {
+			status, result := get("/healthz")
+			So(status, ShouldEqual, http.StatusOK)
+			So(result.Status, ShouldEqual, model.HealthStatusUp)
+		})
+
+		Convey("Readiness reports every component", func() {
+			mockDatabasePort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+
+			status, result := get("/readyz")
+			So(status, ShouldEqual, http.StatusOK)
+			So(result.Components, ShouldHaveLength, 4)
+		})
+
+		Convey("Readiness is unavailable when a component is down", func() {
+			mockDatabasePort.EXPECT().Ping(gomock.Any()).Return(errors.New("connection refused")).Times(1)
+
+			status, result := get("/readyz")
+			So(status, ShouldEqual, http.StatusServiceUnavailable)
+			So(result.Status, ShouldEqual, model.HealthStatusDown)
+			So(result.Components["database"].Status, ShouldEqual, model.HealthStatusDown)
+		})
+	})
+}
@@ -1,14 +1,20 @@
 package fiber_inbound_adapter
 
 import (
-	"os"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/palantir/stacktrace"
 
+	"prabogo/internal/config"
 	"prabogo/internal/domain"
 	"prabogo/internal/model"
-	"prabogo/utils/activity"
+	"prabogo/utils/deadline"
 	"prabogo/utils/jwt"
+	"prabogo/utils/log"
 )
 
 const (
@@ -17,20 +23,43 @@ const (
 	bearerPrefixLen     = 7
 )
 
+// errUnauthorized is returned for missing or unknown credentials
+var errUnauthorized = stacktrace.NewErrorWithCode(model.ErrorCodeUnauthorized, "Unauthorized")
+
+const (
+	LocalsTransactionID = "transaction_id"
+	LocalsClientID      = "client_id"
+	LocalsAuthUID       = "auth_uid"
+	LocalsAuthEmail     = "auth_email"
+	LocalsAuthClaims    = "auth_claims"
+	LocalsRoles         = "roles"
+)
+
+const (
+	internalClientID  = "internal"
+	defaultRolesClaim = "roles"
+)
+
 type MiddlewareAdapter interface {
 	InternalAuth(a any) error
 	ClientAuth(a any) error
+	Authorize(a any, permissions ...model.Permission) error
+	RateLimit(a any, group string) error
+	RequestTimeout(a any) error
 }
 
 type middlewareAdapter struct {
 	domain domain.Domain
+	config config.Http
 }
 
 func NewMiddlewareAdapter(
 	domain domain.Domain,
+	config config.Http,
 ) MiddlewareAdapter {
 	return &middlewareAdapter{
 		domain: domain,
+		config: config,
 	}
 }
 
@@ -43,23 +72,21 @@ func (h *middlewareAdapter) InternalAuth(a any) error {
 	}
 
 	if bearerToken == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Unauthorized",
-		})
+		return errorResponse(c, errUnauthorized)
 	}
 
-	if bearerToken != os.Getenv("INTERNAL_KEY") {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": "Unauthorized",
-		})
+	if bearerToken != h.config.InternalKey {
+		return errorResponse(c, errUnauthorized)
 	}
 
+	c.Locals(LocalsClientID, internalClientID)
+	c.Locals(LocalsRoles, []string{string(model.RoleInternal)})
 	return c.Next()
 }
 
 func (h *middlewareAdapter) ClientAuth(a any) error {
 	c := a.(*fiber.Ctx)
-	ctx := activity.NewContext("http_client_auth")
+	ctx := requestContext(c, "http_client_auth")
 	authHeader := c.Get(authorizationHeader)
 	var bearerToken string
 	if len(authHeader) > bearerPrefixLen && authHeader[:bearerPrefixLen] == bearerPrefix {
@@ -67,39 +94,165 @@ func (h *middlewareAdapter) ClientAuth(a any) error {
 	}
 
 	if bearerToken == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(model.Response{
-			Success: false,
-			Error:   "Unauthorized",
-		})
+		return errorResponse(c, errUnauthorized)
 	}
 
-	authDriver := os.Getenv("AUTH_DRIVER")
-	if authDriver == "jwt" {
-		jwksURL := os.Getenv("AUTH_JWKS_URL")
+	auth := h.config.Auth
+	switch auth.Driver {
+	case "jwt":
+		claims, err := jwt.GetJWTClaimsWithOptions(bearerToken, auth.JWKSURL, jwtValidationOptions(auth))
+		if err != nil {
+			return errorResponse(c, stacktrace.NewErrorWithCode(model.ErrorCodeUnauthorized, "Unauthorized: %s", err.Error()))
+		}
 
-		_, err := jwt.ValidateJWTWithURL(bearerToken, jwksURL)
+		subject, _ := claims["sub"].(string)
+		email, _ := claims["email"].(string)
+		c.Locals(LocalsClientID, subject)
+		c.Locals(LocalsAuthUID, subject)
+		c.Locals(LocalsAuthEmail, email)
+		c.Locals(LocalsAuthClaims, map[string]interface{}(claims))
+		c.Locals(LocalsRoles, claimRoles(claims, auth.RolesClaim))
+	case "firebase":
+		token, err := jwt.ValidateFirebaseIDToken(bearerToken, auth.FirebaseProjectID, auth.FirebaseCertsURL)
 		if err != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(model.Response{
-				Success: false,
-				Error:   "Unauthorized: " + err.Error(),
-			})
+			return errorResponse(c, stacktrace.NewErrorWithCode(model.ErrorCodeUnauthorized, "Unauthorized: %s", err.Error()))
 		}
-	} else {
-		exists, err := h.domain.Client().IsExists(ctx, bearerToken)
+
+		c.Locals(LocalsClientID, token.UID)
+		c.Locals(LocalsAuthUID, token.UID)
+		c.Locals(LocalsAuthEmail, token.Email)
+		c.Locals(LocalsAuthClaims, token.Claims)
+		c.Locals(LocalsRoles, claimRoles(token.Claims, auth.RolesClaim))
+	default:
+		client, exists, err := h.domain.Client().FindByBearerKey(ctx, bearerToken)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(model.Response{
-				Success: false,
-				Error:   err.Error(),
-			})
+			log.WithContext(ctx).Errorf("client auth error %s", err.Error())
+			return errorResponse(c, err)
 		}
 
 		if !exists {
-			return c.Status(fiber.StatusUnauthorized).JSON(model.Response{
-				Success: false,
-				Error:   "Unauthorized",
-			})
+			return errorResponse(c, errUnauthorized)
+		}
+
+		c.Locals(LocalsClientID, strconv.Itoa(client.ID))
+		c.Locals(LocalsRoles, model.RolesToStrings(client.Roles))
+	}
+
+	return c.Next()
+}
+
+func (h *middlewareAdapter) Authorize(a any, permissions ...model.Permission) error {
+	c := a.(*fiber.Ctx)
+	roles, _ := c.Locals(LocalsRoles).([]string)
+	for _, permission := range permissions {
+		if !model.HasPermission(model.RolesFromStrings(roles), permission) {
+			return errorResponse(c, stacktrace.NewErrorWithCode(model.ErrorCodeForbidden, "Forbidden: missing permission %s", permission))
+		}
+	}
+
+	return c.Next()
+}
+
+// RateLimit throttles the request with the limit configured for the route group,
+// overridden per client. Requests pass through when the group has no limit.
+func (h *middlewareAdapter) RateLimit(a any, group string) error {
+	c := a.(*fiber.Ctx)
+	ctx := requestContext(c, "http_rate_limit")
+	clientID, _ := c.Locals(LocalsClientID).(string)
+
+	rateLimit := h.config.RateLimits[strings.ToLower(group)]
+	limit := rateLimit.Limit
+	if clientLimit, ok := rateLimit.Clients[clientID]; ok && clientID != "" {
+		limit = clientLimit
+	}
+	if limit.Requests == 0 {
+		return c.Next()
+	}
+
+	var key string
+	switch rateLimit.Key {
+	case "ip":
+		key = "ip:" + c.IP()
+	case "route":
+		key = "route:" + c.Method() + " " + c.Path()
+	default:
+		key = "ip:" + c.IP()
+		if clientID != "" {
+			key = "client:" + clientID
 		}
 	}
 
+	result := h.domain.RateLimit().Allow(ctx, group+":"+key, limit)
+	reset := strconv.Itoa(int(math.Ceil(result.Reset.Seconds())))
+	c.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Set("RateLimit-Reset", reset)
+	c.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, int(limit.Window.Seconds())))
+
+	if !result.Allowed {
+		c.Set(fiber.HeaderRetryAfter, reset)
+		return c.Status(fiber.StatusTooManyRequests).JSON(model.Response{
+			Success: false,
+			Error:   "Too Many Requests",
+		})
+	}
+
+	return c.Next()
+}
+
+// claimRoles reads the roles from the claim name, either a space or comma separated
+// string or an array of strings
+func claimRoles(claims map[string]interface{}, name string) []string {
+	if name == "" {
+		name = defaultRolesClaim
+	}
+
+	roles := []string{}
+	switch value := claims[name].(type) {
+	case string:
+		roles = append(roles, splitList(value)...)
+	case []interface{}:
+		for _, item := range value {
+			if role, ok := item.(string); ok {
+				roles = append(roles, role)
+			}
+		}
+	}
+
+	return roles
+}
+
+// jwtValidationOptions builds the JWT claim checks of auth
+func jwtValidationOptions(auth config.Auth) jwt.ValidationOptions {
+	options := jwt.ValidationOptions{
+		Issuer:    auth.JWTIssuer,
+		Audiences: auth.JWTAudiences,
+		Scopes:    auth.JWTScopes,
+		Claims:    map[string]string{},
+		Leeway:    auth.JWTLeeway,
+	}
+
+	for _, claim := range auth.JWTRequiredClaims {
+		name, value, _ := strings.Cut(claim, "=")
+		options.Claims[name] = value
+	}
+
+	return options
+}
+
+func splitList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+// RequestTimeout bounds the request context by HTTP_REQUEST_TIMEOUT and cancels it once the
+// handler returns, so outbound calls still running for the request are abandoned.
+func (h *middlewareAdapter) RequestTimeout(a any) error {
+	c := a.(*fiber.Ctx)
+	ctx, cancel := deadline.WithTimeout(c.UserContext(), h.config.RequestTimeout)
+	defer cancel()
+
+	c.SetUserContext(ctx)
 	return c.Next()
 }
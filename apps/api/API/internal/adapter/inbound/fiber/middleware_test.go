@@ -1,21 +1,35 @@
 package fiber_inbound_adapter_test
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
-	"os"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	jwtlib "github.com/golang-jwt/jwt/v5"
 	"github.com/golang/mock/gomock"
-	"github.com/redis/go-redis/v9"
 	. "github.com/smartystreets/goconvey/convey"
 
 	fiber_inbound_adapter "prabogo/internal/adapter/inbound/fiber"
+	"prabogo/internal/config"
 	"prabogo/internal/domain"
 	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
 	mock_outbound_port "prabogo/tests/mocks/port"
+	"prabogo/utils/jwt"
+	"prabogo/utils/ratelimit"
 )
 
 func TestMiddlewareAdapter(t *testing.T) {
@@ -32,14 +46,21 @@ func TestMiddlewareAdapter(t *testing.T) {
 		mockClientMessagePort := mock_outbound_port.NewMockClientMessagePort(mockCtrl)
 		mockClientCachePort := mock_outbound_port.NewMockClientCachePort(mockCtrl)
 		mockClientWorkflowPort := mock_outbound_port.NewMockClientWorkflowPort(mockCtrl)
+		mockClientRoleDatabasePort := mock_outbound_port.NewMockClientRoleDatabasePort(mockCtrl)
+		mockClientKeyDatabasePort := mock_outbound_port.NewMockClientKeyDatabasePort(mockCtrl)
 
 		mockDatabasePort.EXPECT().Client().Return(mockClientDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().ClientRole().Return(mockClientRoleDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().ClientKey().Return(mockClientKeyDatabasePort).AnyTimes()
 		mockCachePort.EXPECT().Client().Return(mockClientCachePort).AnyTimes()
+		mockClientCachePort.EXPECT().Version(gomock.Any()).Return(int64(0), nil).AnyTimes()
+		mockCachePort.EXPECT().RateLimit().Return(ratelimit.NewMemoryStore()).AnyTimes()
 		mockMessagePort.EXPECT().Client().Return(mockClientMessagePort).AnyTimes()
 		mockWorkflowPort.EXPECT().Client().Return(mockClientWorkflowPort).AnyTimes()
 
 		dom := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockWorkflowPort)
-		adapter := fiber_inbound_adapter.NewAdapter(dom)
+		cfg := config.Http{InternalKey: "valid-key"}
+		adapter := fiber_inbound_adapter.NewAdapter(dom, cfg)
 
 		Convey("InternalAuth", func() {
 			app := fiber.New()
@@ -68,9 +89,6 @@ func TestMiddlewareAdapter(t *testing.T) {
 			})
 
 			Convey("Invalid bearer token", func() {
-				os.Setenv("INTERNAL_KEY", "valid-key")
-				defer os.Unsetenv("INTERNAL_KEY")
-
 				req := httptest.NewRequest(http.MethodGet, "/test", nil)
 				req.Header.Set("Authorization", "Bearer invalid-key")
 				resp, err := app.Test(req)
@@ -80,9 +98,6 @@ func TestMiddlewareAdapter(t *testing.T) {
 			})
 
 			Convey("Valid bearer token", func() {
-				os.Setenv("INTERNAL_KEY", "valid-key")
-				defer os.Unsetenv("INTERNAL_KEY")
-
 				req := httptest.NewRequest(http.MethodGet, "/test", nil)
 				req.Header.Set("Authorization", "Bearer valid-key")
 				resp, err := app.Test(req)
@@ -109,12 +124,17 @@ func TestMiddlewareAdapter(t *testing.T) {
 			app.Get("/test", func(c *fiber.Ctx) error {
 				return c.SendString("OK")
 			})
+			app.Get("/whoami", func(c *fiber.Ctx) error {
+				return c.JSON(fiber.Map{
+					"client_id":      c.Locals(fiber_inbound_adapter.LocalsClientID),
+					"transaction_id": c.Locals(fiber_inbound_adapter.LocalsTransactionID),
+				})
+			})
 
 			clientOutput := model.Client{
 				ID: 1,
 				ClientInput: model.ClientInput{
 					Name:      "Test Client",
-					BearerKey: "valid-client-key",
 					CreatedAt: time.Now(),
 					UpdatedAt: time.Now(),
 				},
@@ -129,7 +149,7 @@ func TestMiddlewareAdapter(t *testing.T) {
 			})
 
 			Convey("Client exists in cache", func() {
-				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(clientOutput, nil).Times(1)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(clientOutput, nil).Times(1)
 
 				req := httptest.NewRequest(http.MethodGet, "/test", nil)
 				req.Header.Set("Authorization", "Bearer valid-client-key")
@@ -140,10 +160,13 @@ func TestMiddlewareAdapter(t *testing.T) {
 			})
 
 			Convey("Client exists in database (cache miss)", func() {
-				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
-				mockClientDatabasePort.EXPECT().IsExists(gomock.Any()).Return(true, nil).Times(1)
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return([]model.Client{clientOutput}, nil).Times(1)
-				mockClientCachePort.EXPECT().Set(gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, outbound_port.ErrCacheMiss).Times(1)
+				clientKey := model.ClientKey{ClientKeyInput: model.ClientKeyInput{ClientID: 1}}
+				model.ClientKeyPrepare(&clientKey.ClientKeyInput, "valid-client-key")
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return([]model.ClientKey{clientKey}, nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return([]model.Client{clientOutput}, nil).Times(1)
+				mockClientRoleDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				mockClientCachePort.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
 
 				req := httptest.NewRequest(http.MethodGet, "/test", nil)
 				req.Header.Set("Authorization", "Bearer valid-client-key")
@@ -153,9 +176,25 @@ func TestMiddlewareAdapter(t *testing.T) {
 				So(resp.StatusCode, ShouldEqual, http.StatusOK)
 			})
 
+			Convey("Client ID and transaction ID are stored in locals", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(clientOutput, nil).Times(1)
+
+				req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+				req.Header.Set("Authorization", "Bearer valid-client-key")
+				resp, err := app.Test(req)
+				So(err, ShouldBeNil)
+				defer resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+
+				var body map[string]interface{}
+				So(json.NewDecoder(resp.Body).Decode(&body), ShouldBeNil)
+				So(body["client_id"], ShouldEqual, "1")
+				So(body["transaction_id"], ShouldNotBeEmpty)
+			})
+
 			Convey("Client does not exist", func() {
-				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
-				mockClientDatabasePort.EXPECT().IsExists(gomock.Any()).Return(false, nil).Times(1)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, outbound_port.ErrCacheMiss).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
 
 				req := httptest.NewRequest(http.MethodGet, "/test", nil)
 				req.Header.Set("Authorization", "Bearer nonexistent-key")
@@ -165,5 +204,389 @@ func TestMiddlewareAdapter(t *testing.T) {
 				So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
 			})
 		})
+
+		Convey("Authorize", func() {
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				return adapter.Middleware().ClientAuth(c)
+			})
+			app.Post("/client-upsert", func(c *fiber.Ctx) error {
+				return adapter.Middleware().Authorize(c, model.PermissionClientWrite)
+			}, func(c *fiber.Ctx) error {
+				return c.SendString("OK")
+			})
+
+			clientOutput := model.Client{
+				ID: 1,
+				ClientInput: model.ClientInput{
+					Name: "Test Client",
+				},
+			}
+
+			Convey("Role without the permission", func() {
+				clientOutput.Roles = []model.Role{model.RoleTenant}
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(clientOutput, nil).Times(1)
+
+				req := httptest.NewRequest(http.MethodPost, "/client-upsert", nil)
+				req.Header.Set("Authorization", "Bearer valid-client-key")
+				resp, err := app.Test(req)
+				So(err, ShouldBeNil)
+				defer resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusForbidden)
+			})
+
+			Convey("Role with the permission", func() {
+				clientOutput.Roles = []model.Role{model.RoleManager}
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(clientOutput, nil).Times(1)
+
+				req := httptest.NewRequest(http.MethodPost, "/client-upsert", nil)
+				req.Header.Set("Authorization", "Bearer valid-client-key")
+				resp, err := app.Test(req)
+				So(err, ShouldBeNil)
+				defer resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+			})
+		})
+
+		Convey("RateLimit", func() {
+			group := fmt.Sprintf("test%d", time.Now().UnixNano())
+			limit := func(requests int) ratelimit.Limit {
+				return ratelimit.Limit{Requests: requests, Window: time.Minute}
+			}
+
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals(fiber_inbound_adapter.LocalsClientID, c.Get("X-Client-ID"))
+				return c.Next()
+			})
+			app.Use(func(c *fiber.Ctx) error {
+				return adapter.Middleware().RateLimit(c, group)
+			})
+			app.Get("/test", func(c *fiber.Ctx) error {
+				return c.SendString("OK")
+			})
+
+			request := func(clientID string) *http.Response {
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.Header.Set("X-Client-ID", clientID)
+				resp, err := app.Test(req)
+				So(err, ShouldBeNil)
+				return resp
+			}
+
+			Convey("No limit configured", func() {
+				resp := request("1")
+				defer resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+				So(resp.Header.Get("RateLimit-Limit"), ShouldBeEmpty)
+			})
+
+			Convey("Limit per client", func() {
+				cfg.RateLimits = map[string]config.RateLimit{group: {Limit: limit(2)}}
+				adapter = fiber_inbound_adapter.NewAdapter(dom, cfg)
+
+				for i := 2; i > 0; i-- {
+					resp := request("1")
+					resp.Body.Close()
+					So(resp.StatusCode, ShouldEqual, http.StatusOK)
+					So(resp.Header.Get("RateLimit-Limit"), ShouldEqual, "2")
+					So(resp.Header.Get("RateLimit-Remaining"), ShouldEqual, strconv.Itoa(i-1))
+					So(resp.Header.Get("RateLimit-Policy"), ShouldEqual, "2;w=60")
+				}
+
+				resp := request("1")
+				resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusTooManyRequests)
+				So(resp.Header.Get("Retry-After"), ShouldNotBeEmpty)
+
+				resp = request("2")
+				resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+			})
+
+			Convey("Client override", func() {
+				cfg.RateLimits = map[string]config.RateLimit{group: {
+					Limit:   limit(1),
+					Clients: map[string]ratelimit.Limit{"1": limit(3)},
+				}}
+				adapter = fiber_inbound_adapter.NewAdapter(dom, cfg)
+
+				for i := 0; i < 3; i++ {
+					resp := request("1")
+					resp.Body.Close()
+					So(resp.StatusCode, ShouldEqual, http.StatusOK)
+				}
+
+				resp := request("1")
+				resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusTooManyRequests)
+			})
+
+			Convey("Limit per route", func() {
+				cfg.RateLimits = map[string]config.RateLimit{group: {Limit: limit(1), Key: "route"}}
+				adapter = fiber_inbound_adapter.NewAdapter(dom, cfg)
+
+				resp := request("1")
+				resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+
+				resp = request("2")
+				resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusTooManyRequests)
+			})
+		})
+
+		Convey("RequestTimeout", func() {
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				return adapter.Middleware().RequestTimeout(c)
+			})
+			app.Get("/test", func(c *fiber.Ctx) error {
+				deadline, ok := c.UserContext().Deadline()
+				if !ok {
+					return c.SendString("none")
+				}
+				return c.SendString(strconv.Itoa(int(time.Until(deadline).Round(time.Second).Seconds())))
+			})
+
+			request := func() string {
+				resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/test", nil))
+				So(err, ShouldBeNil)
+				defer resp.Body.Close()
+				body, err := io.ReadAll(resp.Body)
+				So(err, ShouldBeNil)
+				return string(body)
+			}
+
+			Convey("Configured deadline", func() {
+				cfg.RequestTimeout = 5 * time.Second
+				adapter = fiber_inbound_adapter.NewAdapter(dom, cfg)
+				So(request(), ShouldEqual, "5")
+			})
+
+			Convey("Disabled deadline", func() {
+				So(request(), ShouldEqual, "none")
+			})
+		})
+
+		Convey("ClientAuth with firebase driver", func() {
+			privateKey, certPEM := newFirebaseTestCertificate()
+			kid := fmt.Sprintf("test-kid-%d", time.Now().UnixNano())
+			certServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]string{kid: certPEM})
+			}))
+			defer certServer.Close()
+
+			cfg.Auth = config.Auth{
+				Driver:            "firebase",
+				FirebaseProjectID: "kost-test",
+				FirebaseCertsURL:  certServer.URL,
+			}
+			adapter = fiber_inbound_adapter.NewAdapter(dom, cfg)
+
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				return adapter.Middleware().ClientAuth(c)
+			})
+			app.Get("/test", func(c *fiber.Ctx) error {
+				return c.JSON(fiber.Map{
+					"uid":    c.Locals(fiber_inbound_adapter.LocalsAuthUID),
+					"email":  c.Locals(fiber_inbound_adapter.LocalsAuthEmail),
+					"claims": c.Locals(fiber_inbound_adapter.LocalsAuthClaims),
+				})
+			})
+
+			now := time.Now()
+			claims := jwtlib.MapClaims{
+				"iss":       "https://securetoken.google.com/kost-test",
+				"aud":       "kost-test",
+				"sub":       "firebase-uid",
+				"auth_time": now.Add(-time.Minute).Unix(),
+				"iat":       now.Add(-time.Minute).Unix(),
+				"exp":       now.Add(time.Hour).Unix(),
+				"email":     "owner@kost.test",
+				"role":      "owner",
+			}
+
+			Convey("Valid token", func() {
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.Header.Set("Authorization", "Bearer "+signTestToken(privateKey, kid, claims))
+				resp, err := app.Test(req)
+				So(err, ShouldBeNil)
+				defer resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+
+				var body map[string]interface{}
+				So(json.NewDecoder(resp.Body).Decode(&body), ShouldBeNil)
+				So(body["uid"], ShouldEqual, "firebase-uid")
+				So(body["email"], ShouldEqual, "owner@kost.test")
+				So(body["claims"], ShouldResemble, map[string]interface{}{"role": "owner"})
+			})
+
+			Convey("Wrong audience", func() {
+				claims["aud"] = "other-project"
+
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.Header.Set("Authorization", "Bearer "+signTestToken(privateKey, kid, claims))
+				resp, err := app.Test(req)
+				So(err, ShouldBeNil)
+				defer resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+			})
+
+			Convey("Wrong issuer", func() {
+				claims["iss"] = "https://securetoken.google.com/other-project"
+
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.Header.Set("Authorization", "Bearer "+signTestToken(privateKey, kid, claims))
+				resp, err := app.Test(req)
+				So(err, ShouldBeNil)
+				defer resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+			})
+
+			Convey("Auth time in the future", func() {
+				claims["auth_time"] = now.Add(time.Hour).Unix()
+
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.Header.Set("Authorization", "Bearer "+signTestToken(privateKey, kid, claims))
+				resp, err := app.Test(req)
+				So(err, ShouldBeNil)
+				defer resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+			})
+
+			Convey("Expired token", func() {
+				claims["exp"] = now.Add(-time.Minute).Unix()
+
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.Header.Set("Authorization", "Bearer "+signTestToken(privateKey, kid, claims))
+				resp, err := app.Test(req)
+				So(err, ShouldBeNil)
+				defer resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+			})
+
+			Convey("Unknown kid", func() {
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.Header.Set("Authorization", "Bearer "+signTestToken(privateKey, "other-kid", claims))
+				resp, err := app.Test(req)
+				So(err, ShouldBeNil)
+				defer resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+			})
+		})
+
+		Convey("ClientAuth with jwt driver", func() {
+			privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+			So(err, ShouldBeNil)
+			jwksServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewEncoder(w).Encode(jwt.JWKSet{Keys: []jwt.JWK{{
+					Kid: "jwt-kid",
+					Kty: "RSA",
+					N:   base64.RawURLEncoding.EncodeToString(privateKey.N.Bytes()),
+					E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privateKey.E)).Bytes()),
+				}}})
+			}))
+			defer jwksServer.Close()
+
+			cfg.Auth = config.Auth{
+				Driver:       "jwt",
+				JWKSURL:      jwksServer.URL,
+				JWTIssuer:    "https://idp.kost.test/",
+				JWTAudiences: []string{"kost-api"},
+				JWTScopes:    []string{"clients:read"},
+			}
+			adapter = fiber_inbound_adapter.NewAdapter(dom, cfg)
+
+			app := fiber.New()
+			app.Use(func(c *fiber.Ctx) error {
+				return adapter.Middleware().ClientAuth(c)
+			})
+			app.Get("/test", func(c *fiber.Ctx) error {
+				claims := c.Locals(fiber_inbound_adapter.LocalsAuthClaims).(map[string]interface{})
+				return c.JSON(fiber.Map{
+					"uid":       c.Locals(fiber_inbound_adapter.LocalsAuthUID),
+					"client_id": c.Locals(fiber_inbound_adapter.LocalsClientID),
+					"scope":     claims["scope"],
+				})
+			})
+
+			claims := jwtlib.MapClaims{
+				"iss":   "https://idp.kost.test/",
+				"aud":   "kost-api",
+				"sub":   "service-account",
+				"scope": "openid clients:read",
+				"exp":   time.Now().Add(time.Hour).Unix(),
+			}
+
+			Convey("Valid token", func() {
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.Header.Set("Authorization", "Bearer "+signTestToken(privateKey, "jwt-kid", claims))
+				resp, err := app.Test(req)
+				So(err, ShouldBeNil)
+				defer resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusOK)
+
+				var body map[string]interface{}
+				So(json.NewDecoder(resp.Body).Decode(&body), ShouldBeNil)
+				So(body["uid"], ShouldEqual, "service-account")
+				So(body["client_id"], ShouldEqual, "service-account")
+				So(body["scope"], ShouldEqual, "openid clients:read")
+			})
+
+			Convey("Token meant for another application", func() {
+				claims["aud"] = "other-app"
+
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.Header.Set("Authorization", "Bearer "+signTestToken(privateKey, "jwt-kid", claims))
+				resp, err := app.Test(req)
+				So(err, ShouldBeNil)
+				defer resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+			})
+
+			Convey("Missing scope", func() {
+				claims["scope"] = "openid"
+
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.Header.Set("Authorization", "Bearer "+signTestToken(privateKey, "jwt-kid", claims))
+				resp, err := app.Test(req)
+				So(err, ShouldBeNil)
+				defer resp.Body.Close()
+				So(resp.StatusCode, ShouldEqual, http.StatusUnauthorized)
+			})
+		})
 	})
 }
+
+func newFirebaseTestCertificate() (*rsa.PrivateKey, string) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "securetoken.system.gserviceaccount.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		panic(err)
+	}
+
+	return privateKey, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func signTestToken(privateKey *rsa.PrivateKey, kid string, claims jwtlib.MapClaims) string {
+	token := jwtlib.NewWithClaims(jwtlib.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(privateKey)
+	if err != nil {
+		panic(err)
+	}
+	return signed
+}
@@ -1,19 +1,23 @@
 package fiber_inbound_adapter
 
 import (
+	"prabogo/internal/config"
 	"prabogo/internal/domain"
 	inbound_port "prabogo/internal/port/inbound"
 )
 
 type adapter struct {
 	domain domain.Domain
+	config config.Http
 }
 
 func NewAdapter(
 	domain domain.Domain,
+	config config.Http,
 ) inbound_port.HttpPort {
 	return &adapter{
 		domain: domain,
+		config: config,
 	}
 }
 
@@ -21,8 +25,12 @@ func (s *adapter) Ping() inbound_port.PingHttpPort {
 	return NewPingAdapter()
 }
 
+func (s *adapter) Health() inbound_port.HealthHttpPort {
+	return NewHealthAdapter(s.domain)
+}
+
 func (s *adapter) Middleware() inbound_port.MiddlewareHttpPort {
-	return NewMiddlewareAdapter(s.domain)
+	return NewMiddlewareAdapter(s.domain, s.config)
 }
 
 func (s *adapter) Client() inbound_port.ClientHttpPort {
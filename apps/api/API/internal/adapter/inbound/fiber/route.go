@@ -5,6 +5,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 
+	"prabogo/internal/model"
 	inbound_port "prabogo/internal/port/inbound"
 )
 
@@ -13,25 +14,64 @@ func InitRoute(
 	app *fiber.App,
 	port inbound_port.HttpPort,
 ) {
+	app.Use(func(c *fiber.Ctx) error {
+		return port.Middleware().RequestTimeout(c)
+	})
+
+	// probes are registered before authentication and rate limiting
+	app.Get("/healthz", func(c *fiber.Ctx) error {
+		return port.Health().Live(c)
+	})
+	app.Get("/readyz", func(c *fiber.Ctx) error {
+		return port.Health().Ready(c)
+	})
+
+	app.Use(rateLimit(port, "global"))
+
 	internal := app.Group("/internal")
 	internal.Use(func(c *fiber.Ctx) error {
 		return port.Middleware().InternalAuth(c)
 	})
-	internal.Post("/client-upsert", func(c *fiber.Ctx) error {
+	internal.Use(rateLimit(port, "internal"))
+	internal.Post("/client-upsert", authorize(port, model.PermissionClientWrite), func(c *fiber.Ctx) error {
 		return port.Client().Upsert(c)
 	})
-	internal.Post("/client-find", func(c *fiber.Ctx) error {
+	internal.Post("/client-find", authorize(port, model.PermissionClientRead), func(c *fiber.Ctx) error {
 		return port.Client().Find(c)
 	})
-	internal.Delete("/client-delete", func(c *fiber.Ctx) error {
+	internal.Delete("/client-delete", authorize(port, model.PermissionClientDelete), func(c *fiber.Ctx) error {
 		return port.Client().Delete(c)
 	})
+	internal.Post("/client-key-upsert", authorize(port, model.PermissionClientWrite), func(c *fiber.Ctx) error {
+		return port.Client().UpsertKey(c)
+	})
+	internal.Post("/client-key-find", authorize(port, model.PermissionClientRead), func(c *fiber.Ctx) error {
+		return port.Client().FindKeys(c)
+	})
+	internal.Delete("/client-key-delete", authorize(port, model.PermissionClientWrite), func(c *fiber.Ctx) error {
+		return port.Client().DeleteKeys(c)
+	})
 
 	client := app.Group("/v1")
 	client.Use(func(c *fiber.Ctx) error {
 		return port.Middleware().ClientAuth(c)
 	})
+	client.Use(rateLimit(port, "v1"))
 	client.Get("/ping", func(c *fiber.Ctx) error {
 		return port.Ping().GetResource(c)
 	})
 }
+
+// authorize returns a handler that requires all permissions for the route
+func authorize(port inbound_port.HttpPort, permissions ...model.Permission) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return port.Middleware().Authorize(c, permissions...)
+	}
+}
+
+// rateLimit returns a handler that applies the rate limit of the route group
+func rateLimit(port inbound_port.HttpPort, group string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return port.Middleware().RateLimit(c, group)
+	}
+}
@@ -37,9 +37,16 @@ func (h *clientAdapter) Upsert(a any) bool {
 	results, err := h.domain.Client().Upsert(ctx, payload)
 	if err != nil {
 		log.WithContext(ctx).Errorf("client upsert error %s: %s", err.Error(), string(msg))
+		return acknowledge(err)
 	}
-	ctx = context.WithValue(ctx, activity.Result, results)
+	ctx = context.WithValue(ctx, activity.Result, model.RedactBearerKeys(results))
 
 	log.WithContext(ctx).Info("client upsert success")
 	return true
 }
+
+// acknowledge decides whether a failed message is acked or requeued. Only failures that
+// may pass on a retry are requeued, anything else would fail again on every delivery.
+func acknowledge(err error) bool {
+	return !model.IsTemporary(err)
+}
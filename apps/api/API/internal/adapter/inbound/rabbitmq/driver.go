@@ -0,0 +1,31 @@
+package rabbitmq_inbound_adapter
+
+import (
+	"context"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/driver"
+	"prabogo/utils/lifecycle"
+	"prabogo/utils/rabbitmq"
+)
+
+// Settings are the variables the rabbitmq subscriber reads
+type Settings struct {
+	rabbitmq.Config
+	// UpsertClientQueue is the queue of the upsert_client subscriber
+	UpsertClientQueue string `env:"UPSERT_CLIENT_MESSAGE_SUBSCRIBE" description:"queue of the upsert_client subscriber"`
+}
+
+func init() {
+	driver.RegisterInbound(driver.InboundMessage, driver.New("rabbitmq", "RabbitMQ subscriber", func(settings Settings) driver.InboundFactory {
+		return func(ctx context.Context, domain domain.Domain, manager *lifecycle.Manager, args []string) error {
+			if err := rabbitmq.InitMessage(settings.Config); err != nil {
+				return err
+			}
+			manager.OnStop("message subscriber", func(context.Context) error {
+				return rabbitmq.Close()
+			})
+			return InitRoute(ctx, settings, args, NewAdapter(domain))
+		}
+	}))
+}
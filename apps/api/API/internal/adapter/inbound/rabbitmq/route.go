@@ -2,7 +2,10 @@ package rabbitmq_inbound_adapter
 
 import (
 	"context"
-	"os"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
 
 	"prabogo/internal/model"
 	inbound_port "prabogo/internal/port/inbound"
@@ -10,36 +13,81 @@ import (
 	"prabogo/utils/rabbitmq"
 )
 
+// subscriber consumes queue until ctx is done
+type subscriber struct {
+	queue string
+	run   func(ctx context.Context, queue string) error
+}
+
+// InitRoute runs the subscriber named by args[2] until ctx is done. all runs every
+// subscriber with a queue configured. A subscriber that fails stops the others, InitRoute
+// returns why.
 func InitRoute(
 	ctx context.Context,
+	settings Settings,
 	args []string,
 	port inbound_port.MessagePort,
-) {
-	if len(args) > 2 {
-		switch args[2] {
-		case "upsert_client":
-			log.WithContext(ctx).Info("message subscribe upsert client started")
-			done := make(chan struct{})
-			go func() {
-				err := rabbitmq.Subscriber(
+) error {
+	subscribers := map[string]subscriber{
+		"upsert_client": {
+			queue: settings.UpsertClientQueue,
+			run: func(ctx context.Context, queue string) error {
+				return rabbitmq.Subscriber(
+					ctx,
 					model.UpsertClientMessage,
 					rabbitmq.KindFanOut,
-					os.Getenv("UPSERT_CLIENT_MESSAGE_SUBSCRIBE"),
+					queue,
 					"",
 					func(msg []byte) bool {
 						return port.Client().Upsert(msg)
 					},
 				)
-				if err != nil {
-					log.WithContext(ctx).Errorf("failed to subscribe to %s: %s", model.UpsertClientMessage, err)
-				}
-				close(done)
-			}()
-			<-done
-		default:
-			log.WithContext(ctx).Info("message subscribe not found")
+			},
+		},
+	}
+
+	if len(args) < 3 {
+		log.WithContext(ctx).Info("message subscribe not found")
+		return nil
+	}
+
+	var names []string
+	if args[2] == "all" {
+		for name, s := range subscribers {
+			if s.queue == "" {
+				log.WithContext(ctx).Infof("message subscribe %s skipped, it has no queue", name)
+				continue
+			}
+			names = append(names, name)
 		}
+		sort.Strings(names)
+	} else if _, ok := subscribers[args[2]]; ok {
+		names = []string{args[2]}
 	} else {
 		log.WithContext(ctx).Info("message subscribe not found")
+		return nil
 	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			log.WithContext(ctx).Infof("message subscribe %s started", name)
+			if err := subscribers[name].run(ctx, subscribers[name].queue); err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				errs = append(errs, fmt.Errorf("failed to subscribe %s: %w", name, err))
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
 }
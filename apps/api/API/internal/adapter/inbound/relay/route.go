@@ -0,0 +1,65 @@
+package relay_inbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"prabogo/internal/config"
+	"prabogo/internal/domain"
+	"prabogo/utils/activity"
+	"prabogo/utils/deadline"
+	"prabogo/utils/log"
+)
+
+// InitRoute relays the outbox messages until ctx is done. A full batch is followed by
+// the next one right away, otherwise the relay cleans up the published messages and
+// waits cfg.PollInterval. Failures are logged and retried on the next poll.
+func InitRoute(ctx context.Context, port domain.Domain, cfg config.Outbox) error {
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		relay(ctx, port, cfg.BatchSize, cfg.RelayTimeout)
+
+		cleanup(port, cfg.RelayTimeout)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay publishes batches while they are full and ctx is not done
+func relay(ctx context.Context, port domain.Domain, batchSize int, timeout time.Duration) {
+	for ctx.Err() == nil {
+		found, err := relayBatch(port, timeout)
+		if err != nil || found < batchSize {
+			return
+		}
+	}
+}
+
+// relayBatch publishes one batch within timeout, OUTBOX_RELAY_TIMEOUT. The batch does not
+// use the run context, so one started before shutdown still commits what it published.
+func relayBatch(port domain.Domain, timeout time.Duration) (int, error) {
+	ctx, cancel := deadline.WithTimeout(activity.NewContext("relay_outbox"), timeout)
+	defer cancel()
+
+	found, err := port.Outbox().Relay(ctx)
+	if err != nil {
+		log.WithContext(ctx).Errorf("outbox relay error %s", err.Error())
+	}
+	return found, err
+}
+
+// cleanup deletes the published messages past their retention within timeout
+func cleanup(port domain.Domain, timeout time.Duration) {
+	ctx, cancel := deadline.WithTimeout(activity.NewContext("relay_outbox_cleanup"), timeout)
+	defer cancel()
+
+	if err := port.Outbox().Cleanup(ctx); err != nil {
+		log.WithContext(ctx).Errorf("outbox cleanup error %s", err.Error())
+	}
+}
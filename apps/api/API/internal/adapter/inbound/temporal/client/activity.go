@@ -0,0 +1,27 @@
+package client_temporal_inbound_adapter
+
+import (
+	"context"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+)
+
+// ClientActivities are the activities of the client workflows. Temporal registers every
+// exported method of a struct, so it holds only methods returning a result and an
+// error rather than exposing the whole client domain.
+type ClientActivities struct {
+	domain domain.Domain
+}
+
+func NewClientActivities(
+	domain domain.Domain,
+) *ClientActivities {
+	return &ClientActivities{
+		domain: domain,
+	}
+}
+
+func (a *ClientActivities) Upsert(ctx context.Context, inputs []model.ClientInput) ([]model.Client, error) {
+	return a.domain.Client().Upsert(ctx, inputs)
+}
@@ -1,7 +1,9 @@
 package client_temporal_inbound_adapter
 
 import (
-	"go.temporal.io/sdk/worker"
+	"context"
+	"fmt"
+	"time"
 
 	"prabogo/internal/domain"
 	"prabogo/internal/model"
@@ -12,34 +14,38 @@ import (
 )
 
 type clientAdapter struct {
-	domain domain.Domain
+	domain      domain.Domain
+	stopTimeout time.Duration
 }
 
 func NewClientAdapter(
 	domain domain.Domain,
+	stopTimeout time.Duration,
 ) inbound_port.ClientWorkflowPort {
 	return &clientAdapter{
-		domain: domain,
+		domain:      domain,
+		stopTimeout: stopTimeout,
 	}
 }
 
-func (a *clientAdapter) Upsert() {
-	ctx := activity.NewContext("upsert_client_worker")
+func (a *clientAdapter) Upsert(ctx context.Context) error {
+	ctx = activity.WithAction(ctx, "upsert_client_worker")
 
-	w, err := temporal.NewWorker(ctx, model.UpsertClientWorkflowName)
+	w, err := temporal.NewWorker(ctx, model.UpsertClientWorkflowName, a.stopTimeout)
 	if err != nil {
-		log.WithContext(ctx).Error("Unable to create worker", err)
-		return
+		return fmt.Errorf("unable to create worker: %w", err)
 	}
 
 	workflow := NewClientWorkflow(a.domain)
 
 	w.RegisterWorkflow(workflow.UpsertClientWorkflow)
-	w.RegisterActivity(a.domain.Client())
+	w.RegisterActivity(NewClientActivities(a.domain))
 
-	err = w.Run(worker.InterruptCh())
+	err = w.Run(temporal.InterruptCh(ctx))
 	if err != nil {
-		log.WithContext(ctx).Error("Unable to start worker", err)
-		return
+		return fmt.Errorf("unable to run worker: %w", err)
 	}
+
+	log.WithContext(ctx).Info("upsert client worker stopped")
+	return nil
 }
@@ -0,0 +1,81 @@
+package client_temporal_inbound_adapter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.temporal.io/sdk/testsuite"
+
+	client_temporal_inbound_adapter "prabogo/internal/adapter/inbound/temporal/client"
+	"prabogo/internal/domain"
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	mock_outbound_port "prabogo/tests/mocks/port"
+)
+
+func TestClientActivities(t *testing.T) {
+	Convey("Test Client Temporal Activities", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		mockWorkflowPort := mock_outbound_port.NewMockWorkflowPort(mockCtrl)
+
+		mockClientDatabasePort := mock_outbound_port.NewMockClientDatabasePort(mockCtrl)
+		mockClientKeyDatabasePort := mock_outbound_port.NewMockClientKeyDatabasePort(mockCtrl)
+		mockClientCachePort := mock_outbound_port.NewMockClientCachePort(mockCtrl)
+
+		mockDatabasePort.EXPECT().Client().Return(mockClientDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().ClientKey().Return(mockClientKeyDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, txFunc outbound_port.InTransaction) (interface{}, error) {
+				return txFunc(mockDatabasePort)
+			}).AnyTimes()
+		mockCachePort.EXPECT().Client().Return(mockClientCachePort).AnyTimes()
+
+		dom := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockWorkflowPort)
+		activities := client_temporal_inbound_adapter.NewClientActivities(dom)
+
+		var suite testsuite.WorkflowTestSuite
+		env := suite.NewTestActivityEnvironment()
+
+		Convey("Registers with the worker", func() {
+			So(func() { env.RegisterActivity(activities) }, ShouldNotPanic)
+		})
+
+		Convey("Upsert", func() {
+			env.RegisterActivity(activities)
+			inputs := []model.ClientInput{{Name: "Test Client"}}
+
+			Convey("Success", func() {
+				outputs := []model.Client{{ID: 1, ClientInput: model.ClientInput{Name: "Test Client"}}}
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), true).Return(outputs, nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteByClientIDs(gomock.Any(), []int{1}).Return(nil).Times(1)
+
+				value, err := env.ExecuteActivity(activities.Upsert, inputs)
+				So(err, ShouldBeNil)
+
+				var results []model.Client
+				So(value.Get(&results), ShouldBeNil)
+				So(results, ShouldHaveLength, 1)
+				So(results[0].ID, ShouldEqual, 1)
+				So(results[0].BearerKey, ShouldNotBeEmpty)
+			})
+
+			Convey("Domain error", func() {
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(errors.New("database error")).Times(1)
+
+				_, err := env.ExecuteActivity(activities.Upsert, inputs)
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -14,14 +14,14 @@ type ClientWorkflow interface {
 }
 
 type clientWorkflow struct {
-	domain domain.Domain
+	activities *ClientActivities
 }
 
 func NewClientWorkflow(
 	domain domain.Domain,
 ) ClientWorkflow {
 	return &clientWorkflow{
-		domain: domain,
+		activities: NewClientActivities(domain),
 	}
 }
 
@@ -39,7 +39,7 @@ func (g *clientWorkflow) UpsertClientWorkflow(ctx workflow.Context, input model.
 	var results []model.Client
 	err := workflow.ExecuteActivity(
 		ctx,
-		g.domain.Client().Upsert,
+		g.activities.Upsert,
 		[]model.ClientInput{input},
 	).Get(ctx, &results)
 	if err != nil {
@@ -52,8 +52,10 @@ func (g *clientWorkflow) UpsertClientWorkflow(ctx workflow.Context, input model.
 		bearerKey = results[0].BearerKey
 	}
 
-	successMessage := "Bearer key: " + bearerKey
-	logger.Info(successMessage, "WorkflowID", workflowInfo.WorkflowExecution.ID)
+	logger.Info("Workflow completed", "WorkflowID", workflowInfo.WorkflowExecution.ID)
 
-	return successMessage, nil
+	if bearerKey == "" {
+		return "Client upserted, existing keys are unchanged", nil
+	}
+	return "Bearer key: " + bearerKey, nil
 }
@@ -0,0 +1,23 @@
+package temporal_inbound_adapter
+
+import (
+	"context"
+
+	"prabogo/internal/domain"
+	"prabogo/internal/driver"
+	"prabogo/utils/lifecycle"
+	"prabogo/utils/temporal"
+)
+
+func init() {
+	driver.RegisterInbound(driver.InboundWorkflow, driver.New("temporal", "Temporal worker", func(settings temporal.Config) driver.InboundFactory {
+		return func(ctx context.Context, domain domain.Domain, manager *lifecycle.Manager, args []string) error {
+			temporal.Init(settings)
+			manager.OnStop("workflow worker", func(context.Context) error {
+				temporal.Close()
+				return nil
+			})
+			return InitRoute(ctx, args, NewAdapter(domain, manager.Timeout()))
+		}
+	}))
+}
@@ -1,23 +1,30 @@
 package temporal_inbound_adapter
 
 import (
+	"time"
+
 	client_temporal_inbound_adapter "prabogo/internal/adapter/inbound/temporal/client"
 	"prabogo/internal/domain"
 	inbound_port "prabogo/internal/port/inbound"
 )
 
 type adapter struct {
-	domain domain.Domain
+	domain      domain.Domain
+	stopTimeout time.Duration
 }
 
+// NewAdapter creates the workers, which wait up to stopTimeout for running activities
+// when they stop
 func NewAdapter(
 	domain domain.Domain,
+	stopTimeout time.Duration,
 ) inbound_port.WorkflowPort {
 	return &adapter{
-		domain: domain,
+		domain:      domain,
+		stopTimeout: stopTimeout,
 	}
 }
 
 func (a *adapter) Client() inbound_port.ClientWorkflowPort {
-	return client_temporal_inbound_adapter.NewClientAdapter(a.domain)
+	return client_temporal_inbound_adapter.NewClientAdapter(a.domain, a.stopTimeout)
 }
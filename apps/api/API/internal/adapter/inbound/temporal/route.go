@@ -2,25 +2,63 @@ package temporal_inbound_adapter
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
 
 	inbound_port "prabogo/internal/port/inbound"
 	"prabogo/utils/log"
 )
 
+// InitRoute runs the worker named by args[2] until ctx is done, all runs every worker.
+// A worker that fails stops the others, InitRoute returns why.
 func InitRoute(
 	ctx context.Context,
 	args []string,
 	port inbound_port.WorkflowPort,
-) {
-	if len(args) > 2 {
-		switch args[2] {
-		case "upsert_client":
-			port.Client().Upsert()
-			return
-		default:
-			log.WithContext(ctx).Info("command not found")
+) error {
+	workers := map[string]func(ctx context.Context) error{
+		"upsert_client": port.Client().Upsert,
+	}
+
+	if len(args) < 3 {
+		log.WithContext(ctx).Info("command not found")
+		return nil
+	}
+
+	var names []string
+	if args[2] == "all" {
+		for name := range workers {
+			names = append(names, name)
 		}
+		sort.Strings(names)
+	} else if _, ok := workers[args[2]]; ok {
+		names = []string{args[2]}
 	} else {
 		log.WithContext(ctx).Info("command not found")
+		return nil
 	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var errs []error
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := workers[name](ctx); err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				errs = append(errs, fmt.Errorf("failed to run worker %s: %w", name, err))
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
 }
@@ -0,0 +1,115 @@
+package lazy_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"prabogo/internal/model"
+)
+
+type clientDatabaseAdapter struct {
+	*databaseAdapter
+}
+
+func (s *clientDatabaseAdapter) Upsert(ctx context.Context, datas []model.ClientInput) error {
+	return s.port(ctx).Client().Upsert(ctx, datas)
+}
+
+func (s *clientDatabaseAdapter) FindByFilter(ctx context.Context, filter model.ClientFilter, lock bool) ([]model.Client, error) {
+	return s.port(ctx).Client().FindByFilter(ctx, filter, lock)
+}
+
+func (s *clientDatabaseAdapter) CountByFilter(ctx context.Context, filter model.ClientFilter) (int, error) {
+	return s.port(ctx).Client().CountByFilter(ctx, filter)
+}
+
+func (s *clientDatabaseAdapter) DeleteByFilter(ctx context.Context, filter model.ClientFilter) error {
+	return s.port(ctx).Client().DeleteByFilter(ctx, filter)
+}
+
+type clientRoleDatabaseAdapter struct {
+	*databaseAdapter
+}
+
+func (s *clientRoleDatabaseAdapter) Upsert(ctx context.Context, datas []model.ClientRoleInput) error {
+	return s.port(ctx).ClientRole().Upsert(ctx, datas)
+}
+
+func (s *clientRoleDatabaseAdapter) FindByFilter(ctx context.Context, filter model.ClientRoleFilter) ([]model.ClientRole, error) {
+	return s.port(ctx).ClientRole().FindByFilter(ctx, filter)
+}
+
+func (s *clientRoleDatabaseAdapter) DeleteByFilter(ctx context.Context, filter model.ClientRoleFilter) error {
+	return s.port(ctx).ClientRole().DeleteByFilter(ctx, filter)
+}
+
+type clientKeyDatabaseAdapter struct {
+	*databaseAdapter
+}
+
+func (s *clientKeyDatabaseAdapter) Upsert(ctx context.Context, datas []model.ClientKeyInput) error {
+	return s.port(ctx).ClientKey().Upsert(ctx, datas)
+}
+
+func (s *clientKeyDatabaseAdapter) FindByFilter(ctx context.Context, filter model.ClientKeyFilter) ([]model.ClientKey, error) {
+	return s.port(ctx).ClientKey().FindByFilter(ctx, filter)
+}
+
+func (s *clientKeyDatabaseAdapter) DeleteByFilter(ctx context.Context, filter model.ClientKeyFilter) error {
+	return s.port(ctx).ClientKey().DeleteByFilter(ctx, filter)
+}
+
+type clientMessageAdapter struct {
+	*messageAdapter
+}
+
+func (s *clientMessageAdapter) PublishUpsert(ctx context.Context, datas []model.ClientInput) error {
+	return s.port(ctx).Client().PublishUpsert(ctx, datas)
+}
+
+// clientCacheAdapter misses every lookup and skips every write while the cache cannot
+// connect, as nothing stale can be kept then. Evictions return the error instead, so a
+// change is not reported as done while the cache may still hold the replaced client.
+type clientCacheAdapter struct {
+	*cacheAdapter
+}
+
+func (s *clientCacheAdapter) Version(ctx context.Context) (int64, error) {
+	return s.port(ctx).Client().Version(ctx)
+}
+
+func (s *clientCacheAdapter) Set(ctx context.Context, bearerKey string, data model.Client, version int64) error {
+	return s.port(ctx).Client().Set(ctx, bearerKey, data, version)
+}
+
+func (s *clientCacheAdapter) SetUnknown(ctx context.Context, bearerKey string, ttl time.Duration) error {
+	return s.port(ctx).Client().SetUnknown(ctx, bearerKey, ttl)
+}
+
+func (s *clientCacheAdapter) Get(ctx context.Context, bearerKey string) (model.Client, error) {
+	return s.port(ctx).Client().Get(ctx, bearerKey)
+}
+
+func (s *clientCacheAdapter) Delete(ctx context.Context, bearerKey string) error {
+	port, err := s.get(ctx)
+	if err != nil {
+		return err
+	}
+	return port.Client().Delete(ctx, bearerKey)
+}
+
+func (s *clientCacheAdapter) DeleteByClientIDs(ctx context.Context, clientIDs []int) error {
+	port, err := s.get(ctx)
+	if err != nil {
+		return err
+	}
+	return port.Client().DeleteByClientIDs(ctx, clientIDs)
+}
+
+type clientWorkflowAdapter struct {
+	*workflowAdapter
+}
+
+func (s *clientWorkflowAdapter) StartUpsert(ctx context.Context, data model.ClientInput) error {
+	return s.port(ctx).Client().StartUpsert(ctx, data)
+}
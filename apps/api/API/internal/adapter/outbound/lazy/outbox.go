@@ -0,0 +1,40 @@
+package lazy_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"prabogo/internal/model"
+)
+
+type outboxDatabaseAdapter struct {
+	*databaseAdapter
+}
+
+func (s *outboxDatabaseAdapter) Insert(ctx context.Context, datas []model.OutboxMessageInput) error {
+	return s.port(ctx).Outbox().Insert(ctx, datas)
+}
+
+func (s *outboxDatabaseAdapter) FindPending(ctx context.Context, now time.Time, limit int) ([]model.OutboxMessage, error) {
+	return s.port(ctx).Outbox().FindPending(ctx, now, limit)
+}
+
+func (s *outboxDatabaseAdapter) MarkPublished(ctx context.Context, ids []int, publishedAt time.Time) error {
+	return s.port(ctx).Outbox().MarkPublished(ctx, ids, publishedAt)
+}
+
+func (s *outboxDatabaseAdapter) MarkFailed(ctx context.Context, id int, reason string, availableAt time.Time) error {
+	return s.port(ctx).Outbox().MarkFailed(ctx, id, reason, availableAt)
+}
+
+func (s *outboxDatabaseAdapter) DeletePublished(ctx context.Context, before time.Time) error {
+	return s.port(ctx).Outbox().DeletePublished(ctx, before)
+}
+
+type outboxMessageAdapter struct {
+	*messageAdapter
+}
+
+func (s *outboxMessageAdapter) Publish(ctx context.Context, message model.OutboxMessage) error {
+	return s.port(ctx).Outbox().Publish(ctx, message)
+}
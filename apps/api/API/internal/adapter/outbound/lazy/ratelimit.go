@@ -0,0 +1,18 @@
+package lazy_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"prabogo/utils/ratelimit"
+)
+
+// rateLimitStore counts on the store of the cache, or on the shared memory store of the
+// none cache while it cannot connect
+type rateLimitStore struct {
+	*cacheAdapter
+}
+
+func (s *rateLimitStore) Hit(ctx context.Context, key string, limit ratelimit.Limit) (bool, int, time.Time, error) {
+	return s.port(ctx).RateLimit().Hit(ctx, key, limit)
+}
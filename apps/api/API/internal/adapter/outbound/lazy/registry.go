@@ -0,0 +1,290 @@
+package lazy_outbound_adapter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	none_outbound_adapter "prabogo/internal/adapter/outbound/none"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/ratelimit"
+)
+
+const (
+	defaultRetryMin = time.Second
+	defaultRetryMax = 30 * time.Second
+)
+
+// Connect opens a port within ctx, close releases what it opened
+type Connect[T any] func(ctx context.Context) (port T, close func() error, err error)
+
+// Option configures the lazy adapters
+type Option func(*retry)
+
+// retry spaces the connection attempts after a failure, doubling from min up to max
+type retry struct {
+	min time.Duration
+	max time.Duration
+}
+
+// WithRetry waits min after a failed connection before the next attempt, doubling up to
+// max while it keeps failing. Calls in between fail with the last error.
+func WithRetry(min, max time.Duration) Option {
+	return func(r *retry) {
+		r.min, r.max = min, max
+	}
+}
+
+// lazy connects a port on first use, with the context of the call that uses it. Calls
+// arriving during a connection wait for it or for their own context, and a failed
+// connection is retried once its backoff passed.
+type lazy[T any] struct {
+	name    string
+	connect Connect[T]
+	retry   retry
+
+	mu        sync.Mutex
+	port      T
+	close     func() error
+	connected bool
+	stopped   bool
+	dialing   chan struct{}
+	failures  int
+	retryAt   time.Time
+	err       error
+}
+
+func newLazy[T any](name string, connect Connect[T], options []Option) *lazy[T] {
+	l := &lazy[T]{
+		name:    name,
+		connect: connect,
+		retry:   retry{min: defaultRetryMin, max: defaultRetryMax},
+	}
+	for _, option := range options {
+		option(&l.retry)
+	}
+	return l
+}
+
+func (l *lazy[T]) get(ctx context.Context) (T, error) {
+	var zero T
+	for {
+		l.mu.Lock()
+		if l.connected {
+			port := l.port
+			l.mu.Unlock()
+			return port, nil
+		}
+		if l.stopped {
+			l.mu.Unlock()
+			return zero, fmt.Errorf("failed to connect %s: stopped", l.name)
+		}
+
+		if dialing := l.dialing; dialing != nil {
+			l.mu.Unlock()
+			select {
+			case <-dialing:
+				continue
+			case <-ctx.Done():
+				return zero, fmt.Errorf("failed to connect %s: %w", l.name, ctx.Err())
+			}
+		}
+
+		if time.Now().Before(l.retryAt) {
+			err := l.err
+			l.mu.Unlock()
+			return zero, err
+		}
+
+		dialing := make(chan struct{})
+		l.dialing = dialing
+		l.mu.Unlock()
+
+		port, closePort, err := l.connect(ctx)
+		return l.finish(ctx, dialing, port, closePort, err)
+	}
+}
+
+// finish records the result of a connection started by get
+func (l *lazy[T]) finish(ctx context.Context, dialing chan struct{}, port T, closePort func() error, err error) (T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	defer close(dialing)
+	l.dialing = nil
+
+	var zero T
+	if err != nil {
+		err = fmt.Errorf("failed to connect %s: %w", l.name, err)
+		// A caller going away says nothing about the backend, the next call tries again
+		if ctx.Err() == nil {
+			l.err = err
+			l.retryAt = time.Now().Add(l.backoff())
+			l.failures++
+		}
+		return zero, err
+	}
+
+	if l.stopped {
+		if closePort != nil {
+			_ = closePort()
+		}
+		return zero, fmt.Errorf("failed to connect %s: stopped", l.name)
+	}
+
+	l.port, l.close, l.connected = port, closePort, true
+	l.failures, l.retryAt, l.err = 0, time.Time{}, nil
+	return port, nil
+}
+
+// backoff returns the wait after the current failure
+func (l *lazy[T]) backoff() time.Duration {
+	wait := l.retry.min
+	for i := 0; i < l.failures && wait < l.retry.max; i++ {
+		wait *= 2
+	}
+	return min(wait, l.retry.max)
+}
+
+// stop closes the port if it was connected, a connection still in progress is closed
+// once it completes
+func (l *lazy[T]) stop(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stopped = true
+	if !l.connected || l.close == nil {
+		return nil
+	}
+	l.connected = false
+	return l.close()
+}
+
+type databaseAdapter struct {
+	*lazy[outbound_port.DatabasePort]
+}
+
+// NewDatabaseAdapter creates a database that connects on first use, and its stop hook
+func NewDatabaseAdapter(connect Connect[outbound_port.DatabasePort], options ...Option) (outbound_port.DatabasePort, func(ctx context.Context) error) {
+	s := &databaseAdapter{newLazy("database", connect, options)}
+	return s, s.stop
+}
+
+func (s *databaseAdapter) port(ctx context.Context) outbound_port.DatabasePort {
+	port, err := s.get(ctx)
+	if err != nil {
+		return none_outbound_adapter.NewDatabaseAdapter(err)
+	}
+	return port
+}
+
+func (s *databaseAdapter) Client() outbound_port.ClientDatabasePort {
+	return &clientDatabaseAdapter{s}
+}
+
+func (s *databaseAdapter) ClientRole() outbound_port.ClientRoleDatabasePort {
+	return &clientRoleDatabaseAdapter{s}
+}
+
+func (s *databaseAdapter) ClientKey() outbound_port.ClientKeyDatabasePort {
+	return &clientKeyDatabaseAdapter{s}
+}
+
+func (s *databaseAdapter) Outbox() outbound_port.OutboxDatabasePort {
+	return &outboxDatabaseAdapter{s}
+}
+
+func (s *databaseAdapter) DoInTransaction(ctx context.Context, txFunc outbound_port.InTransaction) (interface{}, error) {
+	return s.port(ctx).DoInTransaction(ctx, txFunc)
+}
+
+func (s *databaseAdapter) Ping(ctx context.Context) error {
+	return s.port(ctx).Ping(ctx)
+}
+
+type messageAdapter struct {
+	*lazy[outbound_port.MessagePort]
+}
+
+// NewMessageAdapter creates a message broker that connects on first use, and its stop hook
+func NewMessageAdapter(connect Connect[outbound_port.MessagePort], options ...Option) (outbound_port.MessagePort, func(ctx context.Context) error) {
+	s := &messageAdapter{newLazy("message", connect, options)}
+	return s, s.stop
+}
+
+func (s *messageAdapter) port(ctx context.Context) outbound_port.MessagePort {
+	port, err := s.get(ctx)
+	if err != nil {
+		return none_outbound_adapter.NewMessageAdapter(err)
+	}
+	return port
+}
+
+func (s *messageAdapter) Client() outbound_port.ClientMessagePort {
+	return &clientMessageAdapter{s}
+}
+
+func (s *messageAdapter) Outbox() outbound_port.OutboxMessagePort {
+	return &outboxMessageAdapter{s}
+}
+
+func (s *messageAdapter) Ping(ctx context.Context) error {
+	return s.port(ctx).Ping(ctx)
+}
+
+type cacheAdapter struct {
+	*lazy[outbound_port.CachePort]
+}
+
+// NewCacheAdapter creates a cache that connects on first use, and its stop hook. Until
+// it connects every lookup is a miss and evictions fail.
+func NewCacheAdapter(connect Connect[outbound_port.CachePort], options ...Option) (outbound_port.CachePort, func(ctx context.Context) error) {
+	s := &cacheAdapter{newLazy("cache", connect, options)}
+	return s, s.stop
+}
+
+func (s *cacheAdapter) port(ctx context.Context) outbound_port.CachePort {
+	port, err := s.get(ctx)
+	if err != nil {
+		return none_outbound_adapter.NewCacheAdapter(err)
+	}
+	return port
+}
+
+func (s *cacheAdapter) Client() outbound_port.ClientCachePort {
+	return &clientCacheAdapter{s}
+}
+
+func (s *cacheAdapter) RateLimit() ratelimit.Store {
+	return &rateLimitStore{s}
+}
+
+func (s *cacheAdapter) Ping(ctx context.Context) error {
+	return s.port(ctx).Ping(ctx)
+}
+
+type workflowAdapter struct {
+	*lazy[outbound_port.WorkflowPort]
+}
+
+// NewWorkflowAdapter creates a workflow service that connects on first use, and its stop hook
+func NewWorkflowAdapter(connect Connect[outbound_port.WorkflowPort], options ...Option) (outbound_port.WorkflowPort, func(ctx context.Context) error) {
+	s := &workflowAdapter{newLazy("workflow", connect, options)}
+	return s, s.stop
+}
+
+func (s *workflowAdapter) port(ctx context.Context) outbound_port.WorkflowPort {
+	port, err := s.get(ctx)
+	if err != nil {
+		return none_outbound_adapter.NewWorkflowAdapter(err)
+	}
+	return port
+}
+
+func (s *workflowAdapter) Client() outbound_port.ClientWorkflowPort {
+	return &clientWorkflowAdapter{s}
+}
+
+func (s *workflowAdapter) Ping(ctx context.Context) error {
+	return s.port(ctx).Ping(ctx)
+}
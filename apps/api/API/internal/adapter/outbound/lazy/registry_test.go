@@ -0,0 +1,125 @@
+package lazy_outbound_adapter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	lazy_outbound_adapter "prabogo/internal/adapter/outbound/lazy"
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	mock_outbound_port "prabogo/tests/mocks/port"
+)
+
+func TestLazy(t *testing.T) {
+	Convey("Test Lazy Outbound Adapter", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		ctx := context.Background()
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockClientDatabasePort := mock_outbound_port.NewMockClientDatabasePort(mockCtrl)
+		mockDatabasePort.EXPECT().Client().Return(mockClientDatabasePort).AnyTimes()
+
+		connects, closes := 0, 0
+		var connectErr error
+		connect := func(ctx context.Context) (outbound_port.DatabasePort, func() error, error) {
+			connects++
+			if connectErr != nil {
+				return nil, nil, connectErr
+			}
+			return mockDatabasePort, func() error {
+				closes++
+				return nil
+			}, nil
+		}
+		port, stop := lazy_outbound_adapter.NewDatabaseAdapter(connect, lazy_outbound_adapter.WithRetry(0, 0))
+
+		Convey("Connects once on first use", func() {
+			So(connects, ShouldEqual, 0)
+
+			mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			So(port.Client().Upsert(ctx, []model.ClientInput{{Name: "Client A"}}), ShouldBeNil)
+			So(port.Client().Upsert(ctx, []model.ClientInput{{Name: "Client B"}}), ShouldBeNil)
+			So(connects, ShouldEqual, 1)
+
+			So(stop(ctx), ShouldBeNil)
+			So(closes, ShouldEqual, 1)
+		})
+
+		Convey("Fails calls while it cannot connect and retries on the next use", func() {
+			connectErr = errors.New("connection refused")
+			err := port.Client().Upsert(ctx, nil)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "failed to connect database: connection refused")
+
+			connectErr = nil
+			mockDatabasePort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+			So(port.Ping(ctx), ShouldBeNil)
+			So(connects, ShouldEqual, 2)
+		})
+
+		Convey("Fails calls without connecting until the backoff passed", func() {
+			port, _ := lazy_outbound_adapter.NewDatabaseAdapter(connect, lazy_outbound_adapter.WithRetry(time.Hour, time.Hour))
+
+			connectErr = errors.New("connection refused")
+			So(port.Ping(ctx), ShouldNotBeNil)
+			err := port.Client().Upsert(ctx, nil)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "failed to connect database: connection refused")
+			So(connects, ShouldEqual, 1)
+		})
+
+		Convey("A call waits for a connection in progress only until its context is done", func() {
+			dialing, release := make(chan struct{}), make(chan struct{})
+			port, _ := lazy_outbound_adapter.NewDatabaseAdapter(func(ctx context.Context) (outbound_port.DatabasePort, func() error, error) {
+				close(dialing)
+				<-release
+				return mockDatabasePort, nil, nil
+			})
+			done := make(chan error)
+			go func() {
+				done <- port.Ping(ctx)
+			}()
+			<-dialing
+
+			waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+			defer cancel()
+			err := port.Ping(waitCtx)
+			So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+
+			mockDatabasePort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+			close(release)
+			So(<-done, ShouldBeNil)
+		})
+
+		Convey("Stop without use closes nothing", func() {
+			So(stop(ctx), ShouldBeNil)
+			So(connects, ShouldEqual, 0)
+			So(closes, ShouldEqual, 0)
+		})
+	})
+}
+
+func TestLazyCache(t *testing.T) {
+	Convey("Test Lazy Cache Adapter", t, func() {
+		ctx := context.Background()
+		port, _ := lazy_outbound_adapter.NewCacheAdapter(func(ctx context.Context) (outbound_port.CachePort, func() error, error) {
+			return nil, nil, errors.New("connection refused")
+		})
+
+		Convey("Lookups miss while it cannot connect", func() {
+			_, err := port.Client().Get(ctx, "key")
+			So(errors.Is(err, outbound_port.ErrCacheMiss), ShouldBeTrue)
+		})
+
+		Convey("Evictions fail while it cannot connect", func() {
+			So(port.Client().DeleteByClientIDs(ctx, []int{1}), ShouldNotBeNil)
+			So(port.Client().Delete(ctx, "key"), ShouldNotBeNil)
+		})
+	})
+}
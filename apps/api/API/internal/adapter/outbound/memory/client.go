@@ -0,0 +1,69 @@
+package memory_outbound_adapter
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/lru"
+)
+
+// clientTTL matches the TTL of the Redis client cache
+const clientTTL = 24 * time.Hour
+
+type clientEntry struct {
+	client  model.Client
+	unknown bool
+}
+
+type clientAdapter struct {
+	cache *lru.Cache[clientEntry]
+}
+
+func NewClientAdapter(cache *lru.Cache[clientEntry]) outbound_port.ClientCachePort {
+	return &clientAdapter{
+		cache: cache,
+	}
+}
+
+func (adapter *clientAdapter) Version(ctx context.Context) (int64, error) {
+	return adapter.cache.Version(), nil
+}
+
+func (adapter *clientAdapter) Set(ctx context.Context, bearerKey string, data model.Client, version int64) error {
+	data.BearerKey = ""
+	adapter.cache.SetIf(version, model.ClientCacheKey(bearerKey), clientEntry{client: data}, clientTTL)
+	return nil
+}
+
+func (adapter *clientAdapter) SetUnknown(ctx context.Context, bearerKey string, ttl time.Duration) error {
+	adapter.cache.Set(model.ClientCacheKey(bearerKey), clientEntry{unknown: true}, ttl)
+	return nil
+}
+
+func (adapter *clientAdapter) Get(ctx context.Context, bearerKey string) (model.Client, error) {
+	entry, ok := adapter.cache.Get(model.ClientCacheKey(bearerKey))
+	if !ok {
+		return model.Client{}, outbound_port.ErrCacheMiss
+	}
+
+	if entry.unknown {
+		return model.Client{}, outbound_port.ErrClientUnknown
+	}
+
+	return entry.client, nil
+}
+
+func (adapter *clientAdapter) Delete(ctx context.Context, bearerKey string) error {
+	adapter.cache.Delete(model.ClientCacheKey(bearerKey))
+	return nil
+}
+
+func (adapter *clientAdapter) DeleteByClientIDs(ctx context.Context, clientIDs []int) error {
+	adapter.cache.DeleteFunc(func(entry clientEntry) bool {
+		return !entry.unknown && slices.Contains(clientIDs, entry.client.ID)
+	})
+	return nil
+}
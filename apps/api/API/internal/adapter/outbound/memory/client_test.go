@@ -0,0 +1,91 @@
+package memory_outbound_adapter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	memory_outbound_adapter "prabogo/internal/adapter/outbound/memory"
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/redis"
+)
+
+func TestClient(t *testing.T) {
+	Convey("Test Memory Client Cache", t, func() {
+		ctx := context.Background()
+		cachePort := memory_outbound_adapter.NewAdapter(2)
+
+		client := model.Client{ID: 1, ClientInput: model.ClientInput{Name: "Test Client"}}
+
+		Convey("Get missing key", func() {
+			_, err := cachePort.Client().Get(ctx, "missing")
+			So(errors.Is(err, outbound_port.ErrCacheMiss), ShouldBeTrue)
+			So(errors.Is(err, redis.Nil), ShouldBeTrue)
+		})
+
+		Convey("Set and get", func() {
+			So(cachePort.Client().Set(ctx, "key-1", client, 0), ShouldBeNil)
+
+			result, err := cachePort.Client().Get(ctx, "key-1")
+			So(err, ShouldBeNil)
+			So(result.ID, ShouldEqual, 1)
+		})
+
+		Convey("Set unknown expires after ttl", func() {
+			So(cachePort.Client().SetUnknown(ctx, "key-1", 10*time.Millisecond), ShouldBeNil)
+
+			_, err := cachePort.Client().Get(ctx, "key-1")
+			So(errors.Is(err, outbound_port.ErrClientUnknown), ShouldBeTrue)
+
+			time.Sleep(20 * time.Millisecond)
+			_, err = cachePort.Client().Get(ctx, "key-1")
+			So(errors.Is(err, outbound_port.ErrCacheMiss), ShouldBeTrue)
+		})
+
+		Convey("Evicts the least recently used key when full", func() {
+			So(cachePort.Client().Set(ctx, "key-1", client, 0), ShouldBeNil)
+			So(cachePort.Client().Set(ctx, "key-2", client, 0), ShouldBeNil)
+			_, err := cachePort.Client().Get(ctx, "key-1")
+			So(err, ShouldBeNil)
+			So(cachePort.Client().Set(ctx, "key-3", client, 0), ShouldBeNil)
+
+			_, err = cachePort.Client().Get(ctx, "key-2")
+			So(errors.Is(err, outbound_port.ErrCacheMiss), ShouldBeTrue)
+			_, err = cachePort.Client().Get(ctx, "key-1")
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Set skips a client loaded before an eviction", func() {
+			version, err := cachePort.Client().Version(ctx)
+			So(err, ShouldBeNil)
+			So(cachePort.Client().DeleteByClientIDs(ctx, []int{1}), ShouldBeNil)
+			So(cachePort.Client().Set(ctx, "key-1", client, version), ShouldBeNil)
+
+			_, err = cachePort.Client().Get(ctx, "key-1")
+			So(errors.Is(err, outbound_port.ErrCacheMiss), ShouldBeTrue)
+		})
+
+		Convey("Delete", func() {
+			So(cachePort.Client().Set(ctx, "key-1", client, 0), ShouldBeNil)
+			So(cachePort.Client().Delete(ctx, "key-1"), ShouldBeNil)
+
+			_, err := cachePort.Client().Get(ctx, "key-1")
+			So(errors.Is(err, outbound_port.ErrCacheMiss), ShouldBeTrue)
+		})
+
+		Convey("Delete by client ids", func() {
+			So(cachePort.Client().Set(ctx, "key-1", client, 0), ShouldBeNil)
+			So(cachePort.Client().Set(ctx, "key-2", model.Client{ID: 2}, 0), ShouldBeNil)
+			So(cachePort.Client().DeleteByClientIDs(ctx, []int{1}), ShouldBeNil)
+
+			_, err := cachePort.Client().Get(ctx, "key-1")
+			So(errors.Is(err, outbound_port.ErrCacheMiss), ShouldBeTrue)
+			_, err = cachePort.Client().Get(ctx, "key-2")
+			So(err, ShouldBeNil)
+		})
+	})
+}
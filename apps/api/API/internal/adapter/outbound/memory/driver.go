@@ -0,0 +1,22 @@
+package memory_outbound_adapter
+
+import (
+	"context"
+
+	"prabogo/internal/driver"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+// Settings are the variables the memory driver reads
+type Settings struct {
+	// Size is the entries kept per cache
+	Size int `env:"CACHE_MEMORY_SIZE" default:"10000" validate:"min=1" description:"entries kept per cache"`
+}
+
+func init() {
+	driver.RegisterCache(driver.New("memory", "in-process cache, entries are not shared between instances", func(settings Settings) driver.CacheFactory {
+		return func(ctx context.Context) (outbound_port.CachePort, func() error, error) {
+			return NewAdapter(settings.Size), nil, nil
+		}
+	}))
+}
@@ -0,0 +1,42 @@
+package memory_outbound_adapter
+
+import (
+	"context"
+
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/lru"
+	"prabogo/utils/ratelimit"
+)
+
+const defaultCacheSize = 10000
+
+type adapter struct {
+	clientCache    *lru.Cache[clientEntry]
+	rateLimitStore ratelimit.Store
+}
+
+// NewAdapter creates the in-process cache adapter, each cache holds up to size entries
+// or a default when size is not positive
+func NewAdapter(size int) outbound_port.CachePort {
+	if size <= 0 {
+		size = defaultCacheSize
+	}
+
+	return &adapter{
+		clientCache:    lru.New[clientEntry](size),
+		rateLimitStore: ratelimit.NewMemoryStore(),
+	}
+}
+
+// Ping always succeeds, the cache lives in the process
+func (s *adapter) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (s *adapter) Client() outbound_port.ClientCachePort {
+	return NewClientAdapter(s.clientCache)
+}
+
+func (s *adapter) RateLimit() ratelimit.Store {
+	return s.rateLimitStore
+}
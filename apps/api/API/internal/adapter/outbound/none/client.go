@@ -0,0 +1,104 @@
+package none_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+type clientDatabaseAdapter struct {
+	err error
+}
+
+func (s *clientDatabaseAdapter) Upsert(ctx context.Context, datas []model.ClientInput) error {
+	return s.err
+}
+
+func (s *clientDatabaseAdapter) FindByFilter(ctx context.Context, filter model.ClientFilter, lock bool) ([]model.Client, error) {
+	return nil, s.err
+}
+
+func (s *clientDatabaseAdapter) CountByFilter(ctx context.Context, filter model.ClientFilter) (int, error) {
+	return 0, s.err
+}
+
+func (s *clientDatabaseAdapter) DeleteByFilter(ctx context.Context, filter model.ClientFilter) error {
+	return s.err
+}
+
+type clientRoleDatabaseAdapter struct {
+	err error
+}
+
+func (s *clientRoleDatabaseAdapter) Upsert(ctx context.Context, datas []model.ClientRoleInput) error {
+	return s.err
+}
+
+func (s *clientRoleDatabaseAdapter) FindByFilter(ctx context.Context, filter model.ClientRoleFilter) ([]model.ClientRole, error) {
+	return nil, s.err
+}
+
+func (s *clientRoleDatabaseAdapter) DeleteByFilter(ctx context.Context, filter model.ClientRoleFilter) error {
+	return s.err
+}
+
+type clientKeyDatabaseAdapter struct {
+	err error
+}
+
+func (s *clientKeyDatabaseAdapter) Upsert(ctx context.Context, datas []model.ClientKeyInput) error {
+	return s.err
+}
+
+func (s *clientKeyDatabaseAdapter) FindByFilter(ctx context.Context, filter model.ClientKeyFilter) ([]model.ClientKey, error) {
+	return nil, s.err
+}
+
+func (s *clientKeyDatabaseAdapter) DeleteByFilter(ctx context.Context, filter model.ClientKeyFilter) error {
+	return s.err
+}
+
+type clientMessageAdapter struct {
+	err error
+}
+
+func (s *clientMessageAdapter) PublishUpsert(ctx context.Context, datas []model.ClientInput) error {
+	return s.err
+}
+
+// clientCacheAdapter keeps nothing, so the domain always falls back to the database
+type clientCacheAdapter struct{}
+
+func (s *clientCacheAdapter) Version(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (s *clientCacheAdapter) Set(ctx context.Context, bearerKey string, data model.Client, version int64) error {
+	return nil
+}
+
+func (s *clientCacheAdapter) SetUnknown(ctx context.Context, bearerKey string, ttl time.Duration) error {
+	return nil
+}
+
+func (s *clientCacheAdapter) Get(ctx context.Context, bearerKey string) (model.Client, error) {
+	return model.Client{}, outbound_port.ErrCacheMiss
+}
+
+func (s *clientCacheAdapter) Delete(ctx context.Context, bearerKey string) error {
+	return nil
+}
+
+func (s *clientCacheAdapter) DeleteByClientIDs(ctx context.Context, clientIDs []int) error {
+	return nil
+}
+
+type clientWorkflowAdapter struct {
+	err error
+}
+
+func (s *clientWorkflowAdapter) StartUpsert(ctx context.Context, data model.ClientInput) error {
+	return s.err
+}
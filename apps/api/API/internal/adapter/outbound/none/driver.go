@@ -0,0 +1,34 @@
+package none_outbound_adapter
+
+import (
+	"context"
+
+	"prabogo/internal/driver"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+func init() {
+	driver.RegisterDatabase(driver.New(driver.None, "no database, calls fail as not configured", func(struct{}) driver.DatabaseFactory {
+		return func(ctx context.Context) (outbound_port.DatabasePort, func() error, error) {
+			return NewDatabaseAdapter(NotConfigured("database")), nil, nil
+		}
+	}))
+
+	driver.RegisterMessage(driver.New(driver.None, "no message broker, calls fail as not configured", func(struct{}) driver.MessageFactory {
+		return func(ctx context.Context) (outbound_port.MessagePort, func() error, error) {
+			return NewMessageAdapter(NotConfigured("message")), nil, nil
+		}
+	}))
+
+	driver.RegisterCache(driver.New(driver.None, "no cache, every lookup is a miss", func(struct{}) driver.CacheFactory {
+		return func(ctx context.Context) (outbound_port.CachePort, func() error, error) {
+			return NewCacheAdapter(NotConfigured("cache")), nil, nil
+		}
+	}))
+
+	driver.RegisterWorkflow(driver.New(driver.None, "no workflow service, calls fail as not configured", func(struct{}) driver.WorkflowFactory {
+		return func(ctx context.Context) (outbound_port.WorkflowPort, func() error, error) {
+			return NewWorkflowAdapter(NotConfigured("workflow")), nil, nil
+		}
+	}))
+}
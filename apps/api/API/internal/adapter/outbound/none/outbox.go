@@ -0,0 +1,40 @@
+package none_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"prabogo/internal/model"
+)
+
+type outboxDatabaseAdapter struct {
+	err error
+}
+
+func (s *outboxDatabaseAdapter) Insert(ctx context.Context, datas []model.OutboxMessageInput) error {
+	return s.err
+}
+
+func (s *outboxDatabaseAdapter) FindPending(ctx context.Context, now time.Time, limit int) ([]model.OutboxMessage, error) {
+	return nil, s.err
+}
+
+func (s *outboxDatabaseAdapter) MarkPublished(ctx context.Context, ids []int, publishedAt time.Time) error {
+	return s.err
+}
+
+func (s *outboxDatabaseAdapter) MarkFailed(ctx context.Context, id int, reason string, availableAt time.Time) error {
+	return s.err
+}
+
+func (s *outboxDatabaseAdapter) DeletePublished(ctx context.Context, before time.Time) error {
+	return s.err
+}
+
+type outboxMessageAdapter struct {
+	err error
+}
+
+func (s *outboxMessageAdapter) Publish(ctx context.Context, message model.OutboxMessage) error {
+	return s.err
+}
@@ -0,0 +1,112 @@
+package none_outbound_adapter
+
+import (
+	"context"
+	"fmt"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/ratelimit"
+)
+
+// NotConfigured returns the error of a port whose driver is none or not set
+func NotConfigured(port string) error {
+	return fmt.Errorf("%s is %w", port, model.ErrNotConfigured)
+}
+
+type databaseAdapter struct {
+	err error
+}
+
+// NewDatabaseAdapter creates a database whose every call fails with err
+func NewDatabaseAdapter(err error) outbound_port.DatabasePort {
+	return &databaseAdapter{err: err}
+}
+
+func (s *databaseAdapter) Client() outbound_port.ClientDatabasePort {
+	return &clientDatabaseAdapter{err: s.err}
+}
+
+func (s *databaseAdapter) ClientRole() outbound_port.ClientRoleDatabasePort {
+	return &clientRoleDatabaseAdapter{err: s.err}
+}
+
+func (s *databaseAdapter) ClientKey() outbound_port.ClientKeyDatabasePort {
+	return &clientKeyDatabaseAdapter{err: s.err}
+}
+
+func (s *databaseAdapter) Outbox() outbound_port.OutboxDatabasePort {
+	return &outboxDatabaseAdapter{err: s.err}
+}
+
+func (s *databaseAdapter) DoInTransaction(ctx context.Context, txFunc outbound_port.InTransaction) (interface{}, error) {
+	return nil, s.err
+}
+
+func (s *databaseAdapter) Ping(ctx context.Context) error {
+	return s.err
+}
+
+type messageAdapter struct {
+	err error
+}
+
+// NewMessageAdapter creates a message broker whose every call fails with err
+func NewMessageAdapter(err error) outbound_port.MessagePort {
+	return &messageAdapter{err: err}
+}
+
+func (s *messageAdapter) Client() outbound_port.ClientMessagePort {
+	return &clientMessageAdapter{err: s.err}
+}
+
+func (s *messageAdapter) Outbox() outbound_port.OutboxMessagePort {
+	return &outboxMessageAdapter{err: s.err}
+}
+
+func (s *messageAdapter) Ping(ctx context.Context) error {
+	return s.err
+}
+
+// rateLimitStore counts the hits of every none cache, so limits still hold in the
+// process while there is no cache or it cannot connect
+var rateLimitStore = ratelimit.NewMemoryStore()
+
+type cacheAdapter struct {
+	err error
+}
+
+// NewCacheAdapter creates a cache that stores nothing, every lookup is a miss. Ping
+// fails with err, so readiness can tell it apart from a real cache.
+func NewCacheAdapter(err error) outbound_port.CachePort {
+	return &cacheAdapter{err: err}
+}
+
+func (s *cacheAdapter) Client() outbound_port.ClientCachePort {
+	return &clientCacheAdapter{}
+}
+
+func (s *cacheAdapter) RateLimit() ratelimit.Store {
+	return rateLimitStore
+}
+
+func (s *cacheAdapter) Ping(ctx context.Context) error {
+	return s.err
+}
+
+type workflowAdapter struct {
+	err error
+}
+
+// NewWorkflowAdapter creates a workflow service whose every call fails with err
+func NewWorkflowAdapter(err error) outbound_port.WorkflowPort {
+	return &workflowAdapter{err: err}
+}
+
+func (s *workflowAdapter) Client() outbound_port.ClientWorkflowPort {
+	return &clientWorkflowAdapter{err: s.err}
+}
+
+func (s *workflowAdapter) Ping(ctx context.Context) error {
+	return s.err
+}
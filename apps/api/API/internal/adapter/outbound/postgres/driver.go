@@ -0,0 +1,28 @@
+package postgres_outbound_adapter
+
+import (
+	"context"
+
+	sql_outbound_adapter "prabogo/internal/adapter/outbound/sql"
+	"prabogo/internal/driver"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/database"
+)
+
+// Settings are the variables the postgres driver reads
+type Settings struct {
+	database.Config
+	sql_outbound_adapter.Settings
+}
+
+func init() {
+	driver.RegisterDatabase(driver.New("postgres", "PostgreSQL", func(settings Settings) driver.DatabaseFactory {
+		return func(ctx context.Context) (outbound_port.DatabasePort, func() error, error) {
+			db, err := database.InitDatabase(ctx, "postgres", settings.Config)
+			if err != nil {
+				return nil, nil, err
+			}
+			return NewAdapter(db, settings.Options()...), db.Close, nil
+		}
+	}))
+}
@@ -2,20 +2,29 @@ package rabbitmq_outbound_adapter
 
 import (
 	"context"
+	"time"
 
 	"prabogo/internal/model"
 	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/deadline"
 	"prabogo/utils/rabbitmq"
 )
 
-type clientAdapter struct{}
+type clientAdapter struct {
+	timeout time.Duration
+}
 
-func NewClientAdapter() outbound_port.ClientMessagePort {
-	return &clientAdapter{}
+func NewClientAdapter(timeout time.Duration) outbound_port.ClientMessagePort {
+	return &clientAdapter{
+		timeout: timeout,
+	}
 }
 
-func (adapter *clientAdapter) PublishUpsert(datas []model.ClientInput) error {
-	err := rabbitmq.Publish(context.Background(), model.UpsertClientMessage, rabbitmq.KindFanOut, "", datas)
+func (adapter *clientAdapter) PublishUpsert(ctx context.Context, datas []model.ClientInput) error {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	err := rabbitmq.Publish(ctx, model.UpsertClientMessage, rabbitmq.KindFanOut, "", datas)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,27 @@
+package rabbitmq_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"prabogo/internal/driver"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/rabbitmq"
+)
+
+// Settings are the variables the rabbitmq publisher reads
+type Settings struct {
+	rabbitmq.Config
+	Timeout time.Duration `env:"MESSAGE_TIMEOUT" default:"5s" validate:"min=0" description:"deadline of each publish, 0 disables"`
+}
+
+func init() {
+	driver.RegisterMessage(driver.New("rabbitmq", "RabbitMQ publisher", func(settings Settings) driver.MessageFactory {
+		return func(ctx context.Context) (outbound_port.MessagePort, func() error, error) {
+			if err := rabbitmq.InitMessage(settings.Config); err != nil {
+				return nil, nil, err
+			}
+			return NewAdapter(settings.Timeout), rabbitmq.Close, nil
+		}
+	}))
+}
@@ -0,0 +1,31 @@
+package rabbitmq_outbound_adapter
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/deadline"
+	"prabogo/utils/rabbitmq"
+)
+
+type outboxAdapter struct {
+	timeout time.Duration
+}
+
+func NewOutboxAdapter(timeout time.Duration) outbound_port.OutboxMessagePort {
+	return &outboxAdapter{
+		timeout: timeout,
+	}
+}
+
+// Publish publishes the stored payload to the fanout exchange of its topic with the outbox
+// id as the message id. A message can be published again, consumers must be idempotent.
+func (adapter *outboxAdapter) Publish(ctx context.Context, message model.OutboxMessage) error {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	return rabbitmq.PublishBody(ctx, message.Topic, rabbitmq.KindFanOut, "", strconv.Itoa(message.ID), []byte(message.Payload))
+}
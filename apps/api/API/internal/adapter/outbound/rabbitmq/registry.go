@@ -1,16 +1,33 @@
 package rabbitmq_outbound_adapter
 
 import (
+	"context"
+	"time"
+
 	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/rabbitmq"
 )
 
 type adapter struct {
+	timeout time.Duration
+}
+
+// NewAdapter creates the RabbitMQ publishers, timeout bounds each publish and 0 leaves
+// them unbounded
+func NewAdapter(timeout time.Duration) outbound_port.MessagePort {
+	return &adapter{
+		timeout: timeout,
+	}
 }
 
-func NewAdapter() outbound_port.MessagePort {
-	return &adapter{}
+func (s *adapter) Ping(ctx context.Context) error {
+	return rabbitmq.Ping()
 }
 
 func (s *adapter) Client() outbound_port.ClientMessagePort {
-	return NewClientAdapter()
+	return NewClientAdapter(s.timeout)
+}
+
+func (s *adapter) Outbox() outbound_port.OutboxMessagePort {
+	return NewOutboxAdapter(s.timeout)
 }
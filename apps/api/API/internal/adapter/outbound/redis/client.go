@@ -3,39 +3,248 @@ package redis_outbound_adapter
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
 
 	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
 
 	"prabogo/internal/model"
 	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/deadline"
+	"prabogo/utils/log"
 	"prabogo/utils/redis"
 )
 
-type clientAdapter struct{}
+// ClientInvalidateChannel carries client cache invalidations to every instance
+const ClientInvalidateChannel = "client.cache.invalidate"
 
-func NewClientAdapter() outbound_port.ClientCachePort {
-	return &clientAdapter{}
+// ClientInvalidation is broadcast when cached clients change, by cache key or by client ID
+type ClientInvalidation struct {
+	Keys      []string `json:"keys,omitempty"`
+	ClientIDs []int    `json:"client_ids,omitempty"`
 }
 
-func (adapter *clientAdapter) Set(data model.Client) error {
+var (
+	clientInvalidatorsMu sync.RWMutex
+	clientInvalidators   []func(ClientInvalidation)
+)
+
+// OnClientInvalidate registers an in-process cache to be told about invalidations
+// made by any instance
+func OnClientInvalidate(invalidator func(ClientInvalidation)) {
+	clientInvalidatorsMu.Lock()
+	defer clientInvalidatorsMu.Unlock()
+	clientInvalidators = append(clientInvalidators, invalidator)
+}
+
+// ListenClientInvalidations passes broadcast invalidations to the registered in-process
+// caches until ctx is done
+func ListenClientInvalidations(ctx context.Context) {
+	err := redis.Listen(ctx, ClientInvalidateChannel, func(message string) {
+		var invalidation ClientInvalidation
+		if err := json.Unmarshal([]byte(message), &invalidation); err != nil {
+			log.WithContext(ctx).Warnf("invalid client cache invalidation: %v", err)
+			return
+		}
+
+		clientInvalidatorsMu.RLock()
+		defer clientInvalidatorsMu.RUnlock()
+		for _, invalidator := range clientInvalidators {
+			invalidator(invalidation)
+		}
+	})
+	if err != nil && ctx.Err() == nil {
+		log.WithContext(ctx).Errorf("client cache invalidation listener stopped: %v", err)
+	}
+}
+
+const (
+	// clientUnknownValue marks a bearer key cached as unknown
+	clientUnknownValue = "unknown"
+	// clientVersionKey counts the evictions of cached clients
+	clientVersionKey = "client:version"
+	clientTTL        = 24 * time.Hour
+)
+
+type clientAdapter struct {
+	lru     *clientLRU
+	timeout time.Duration
+}
+
+// NewClientAdapter creates the client cache, lru is optional. timeout bounds each call,
+// 0 leaves them unbounded.
+func NewClientAdapter(lru *clientLRU, timeout time.Duration) outbound_port.ClientCachePort {
+	return &clientAdapter{
+		lru:     lru,
+		timeout: timeout,
+	}
+}
+
+func (adapter *clientAdapter) Version(ctx context.Context) (int64, error) {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	return redis.GetInt(ctx, clientVersionKey)
+}
+
+func (adapter *clientAdapter) Set(ctx context.Context, bearerKey string, data model.Client, version int64) error {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	data.BearerKey = ""
 	bytes, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
-	return redis.Set(context.Background(), data.BearerKey, string(bytes))
+
+	var lruVersion int64
+	if adapter.lru != nil {
+		lruVersion = adapter.lru.version()
+	}
+
+	// The value and its index entry are written together, or not at all once evicted
+	key := model.ClientCacheKey(bearerKey)
+	stored, err := redis.SetIndexedIf(ctx, clientVersionKey, version, key, string(bytes), clientIndexKey(data.ID), clientTTL)
+	if err != nil {
+		return err
+	}
+
+	if stored && adapter.lru != nil {
+		adapter.lru.setIf(lruVersion, key, clientLRUEntry{client: data}, 0)
+	}
+
+	return nil
+}
+
+func (adapter *clientAdapter) SetUnknown(ctx context.Context, bearerKey string, ttl time.Duration) error {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	key := model.ClientCacheKey(bearerKey)
+	err := redis.SetWithTTL(ctx, key, clientUnknownValue, ttl)
+	if err != nil {
+		return err
+	}
+
+	if adapter.lru != nil {
+		adapter.lru.set(key, clientLRUEntry{unknown: true}, ttl)
+	}
+
+	return nil
 }
 
-func (adapter *clientAdapter) Get(bearerKey string) (model.Client, error) {
+func (adapter *clientAdapter) Get(ctx context.Context, bearerKey string) (model.Client, error) {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	key := model.ClientCacheKey(bearerKey)
+	if adapter.lru != nil {
+		if entry, ok := adapter.lru.get(key); ok {
+			if entry.unknown {
+				return model.Client{}, outbound_port.ErrClientUnknown
+			}
+			return entry.client, nil
+		}
+	}
+
+	var lruVersion int64
+	if adapter.lru != nil {
+		lruVersion = adapter.lru.version()
+	}
+
 	var client model.Client
-	result, err := redis.Get(context.Background(), bearerKey)
+	result, err := redis.Get(ctx, key)
+	if errors.Is(err, redis.Nil) {
+		return model.Client{}, outbound_port.ErrCacheMiss
+	}
 	if err != nil {
 		return model.Client{}, err
 	}
 
+	if result == clientUnknownValue {
+		return model.Client{}, outbound_port.ErrClientUnknown
+	}
+
 	err = json.Unmarshal([]byte(result), &client)
 	if err != nil {
 		return model.Client{}, err
 	}
 
+	if adapter.lru != nil {
+		adapter.lru.setIf(lruVersion, key, clientLRUEntry{client: client}, 0)
+	}
+
 	return client, nil
 }
+
+func (adapter *clientAdapter) Delete(ctx context.Context, bearerKey string) error {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	key := model.ClientCacheKey(bearerKey)
+	err := redis.Del(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	invalidation := ClientInvalidation{Keys: []string{key}}
+	if adapter.lru != nil {
+		adapter.lru.invalidate(invalidation)
+	}
+
+	return broadcastClientInvalidation(ctx, invalidation)
+}
+
+func (adapter *clientAdapter) DeleteByClientIDs(ctx context.Context, clientIDs []int) error {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	if len(clientIDs) == 0 {
+		return nil
+	}
+
+	// Bumped first, so a lookup that read the database before this change cannot cache
+	// it once the keys below are gone
+	_, err := redis.Incr(ctx, clientVersionKey)
+	if err != nil {
+		return err
+	}
+
+	var keys []string
+	for _, clientID := range clientIDs {
+		indexKey := clientIndexKey(clientID)
+		members, err := redis.SetMembers(ctx, indexKey)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, indexKey)
+		keys = append(keys, members...)
+	}
+
+	err = redis.Del(ctx, keys...)
+	if err != nil {
+		return err
+	}
+
+	invalidation := ClientInvalidation{ClientIDs: clientIDs}
+	if adapter.lru != nil {
+		adapter.lru.invalidate(invalidation)
+	}
+
+	return broadcastClientInvalidation(ctx, invalidation)
+}
+
+// clientIndexKey holds the cache keys of one client so they can be evicted together
+func clientIndexKey(clientID int) string {
+	return "client:index:" + strconv.Itoa(clientID)
+}
+
+func broadcastClientInvalidation(ctx context.Context, invalidation ClientInvalidation) error {
+	bytes, err := json.Marshal(invalidation)
+	if err != nil {
+		return err
+	}
+	return redis.Broadcast(ctx, ClientInvalidateChannel, string(bytes))
+}
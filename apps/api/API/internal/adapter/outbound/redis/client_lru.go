@@ -0,0 +1,74 @@
+package redis_outbound_adapter
+
+import (
+	"time"
+
+	"prabogo/internal/model"
+	"prabogo/utils/lru"
+)
+
+// clientLRU is an in-process tier in front of Redis holding the most recently used
+// clients, and unknown keys, for a short TTL
+type clientLRU struct {
+	ttl   time.Duration
+	cache *lru.Cache[clientLRUEntry]
+}
+
+type clientLRUEntry struct {
+	client  model.Client
+	unknown bool
+}
+
+func newClientLRU(size int, ttl time.Duration) *clientLRU {
+	c := &clientLRU{
+		ttl:   ttl,
+		cache: lru.New[clientLRUEntry](size),
+	}
+	OnClientInvalidate(c.invalidate)
+	return c
+}
+
+func (c *clientLRU) get(key string) (clientLRUEntry, bool) {
+	return c.cache.Get(key)
+}
+
+// version returns the version to read before loading an entry for setIf
+func (c *clientLRU) version() int64 {
+	return c.cache.Version()
+}
+
+// set sets the entry, ttl is capped by the LRU TTL
+func (c *clientLRU) set(key string, entry clientLRUEntry, ttl time.Duration) {
+	c.cache.Set(key, entry, c.capTTL(ttl))
+}
+
+// setIf sets the entry unless an invalidation arrived since version, as the entry may
+// have been loaded before the change it announced
+func (c *clientLRU) setIf(version int64, key string, entry clientLRUEntry, ttl time.Duration) {
+	c.cache.SetIf(version, key, entry, c.capTTL(ttl))
+}
+
+func (c *clientLRU) capTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 || ttl > c.ttl {
+		return c.ttl
+	}
+	return ttl
+}
+
+func (c *clientLRU) invalidate(invalidation ClientInvalidation) {
+	if len(invalidation.Keys) > 0 {
+		c.cache.Delete(invalidation.Keys...)
+	}
+
+	if len(invalidation.ClientIDs) == 0 {
+		return
+	}
+
+	clientIDs := map[int]bool{}
+	for _, clientID := range invalidation.ClientIDs {
+		clientIDs[clientID] = true
+	}
+	c.cache.DeleteFunc(func(entry clientLRUEntry) bool {
+		return clientIDs[entry.client.ID] && !entry.unknown
+	})
+}
@@ -0,0 +1,35 @@
+package redis_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"prabogo/internal/driver"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/redis"
+)
+
+// Settings are the variables the redis driver reads
+type Settings struct {
+	redis.Config
+	// LRUSize is the clients kept in process in front of Redis, 0 disables the tier
+	LRUSize int           `env:"CACHE_LRU_SIZE" default:"0" validate:"min=0" description:"clients kept in process in front of Redis, 0 disables"`
+	LRUTTL  time.Duration `env:"CACHE_LRU_TTL" default:"30s" description:"how long in-process clients are kept"`
+	Timeout time.Duration `env:"CACHE_TIMEOUT" default:"1s" validate:"min=0" description:"deadline of each call, 0 disables"`
+}
+
+func init() {
+	driver.RegisterCache(driver.New("redis", "Redis, shared between instances", func(settings Settings) driver.CacheFactory {
+		return func(ctx context.Context) (outbound_port.CachePort, func() error, error) {
+			redis.InitDatabase(settings.Config)
+			// ctx only bounds the connection, the listener runs until close
+			listenCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+			go ListenClientInvalidations(listenCtx)
+
+			return NewAdapter(settings.LRUSize, settings.LRUTTL, settings.Timeout), func() error {
+				cancel()
+				return redis.Close()
+			}, nil
+		}
+	}))
+}
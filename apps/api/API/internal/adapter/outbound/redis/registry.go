@@ -1,16 +1,51 @@
 package redis_outbound_adapter
 
 import (
+	"context"
+	"time"
+
 	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/deadline"
+	"prabogo/utils/ratelimit"
+	"prabogo/utils/redis"
 )
 
+const defaultClientLRUTTL = 30 * time.Second
+
 type adapter struct {
+	clientLRU *clientLRU
+	timeout   time.Duration
 }
 
-func NewAdapter() outbound_port.CachePort {
-	return &adapter{}
+// NewAdapter creates the Redis cache adapter, with an in-process LRU tier of lruSize
+// clients kept for lruTTL when lruSize is positive. timeout bounds each call, 0 leaves
+// them unbounded.
+func NewAdapter(lruSize int, lruTTL time.Duration, timeout time.Duration) outbound_port.CachePort {
+	s := &adapter{
+		timeout: timeout,
+	}
+
+	if lruSize > 0 {
+		if lruTTL <= 0 {
+			lruTTL = defaultClientLRUTTL
+		}
+		s.clientLRU = newClientLRU(lruSize, lruTTL)
+	}
+
+	return s
+}
+
+func (s *adapter) Ping(ctx context.Context) error {
+	ctx, cancel := deadline.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	return redis.Ping(ctx)
 }
 
 func (s *adapter) Client() outbound_port.ClientCachePort {
-	return NewClientAdapter()
+	return NewClientAdapter(s.clientLRU, s.timeout)
+}
+
+func (s *adapter) RateLimit() ratelimit.Store {
+	return ratelimit.NewRedisStore()
 }
@@ -0,0 +1,183 @@
+package sql_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/deadline"
+
+	"github.com/doug-martin/goqu/v9"
+	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
+	_ "github.com/doug-martin/goqu/v9/dialect/sqlite3"
+	"github.com/doug-martin/goqu/v9/exp"
+)
+
+const tableClient = "clients"
+
+// clientSortColumns are the kinds of model.ClientSortFields
+var clientSortColumns = map[string]columnKind{
+	"id":         kindInt,
+	"name":       kindString,
+	"created_at": kindTime,
+	"updated_at": kindTime,
+}
+
+type clientAdapter struct {
+	db      outbound_port.DatabaseExecutor
+	dialect string
+	timeout time.Duration
+}
+
+func NewClientAdapter(
+	db outbound_port.DatabaseExecutor,
+	dialect string,
+	timeout time.Duration,
+) outbound_port.ClientDatabasePort {
+	return &clientAdapter{
+		db:      db,
+		dialect: dialect,
+		timeout: timeout,
+	}
+}
+
+func (adapter *clientAdapter) Upsert(ctx context.Context, datas []model.ClientInput) error {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	dataset := goqu.Dialect(adapter.dialect).
+		Insert(tableClient).
+		Rows(datas).
+		OnConflict(goqu.DoUpdate("name", goqu.Record{"updated_at": goqu.L("EXCLUDED.updated_at")}))
+
+	query, args, err := dataset.Prepared(true).ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = adapter.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return databaseError(err)
+	}
+
+	return nil
+}
+
+func (adapter *clientAdapter) FindByFilter(ctx context.Context, filter model.ClientFilter, lock bool) (result []model.Client, err error) {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	dialect := goqu.Dialect(adapter.dialect)
+	dataset := dialect.From(tableClient).
+		Select("id", "name", "created_at", "updated_at")
+	dataset = addFilter(dataset, filter)
+
+	if len(filter.Sort) > 0 || filter.Page != (model.Pagination{}) {
+		sorts, err := model.NormalizeSort(filter.Sort, model.ClientSortFields...)
+		if err != nil {
+			return nil, err
+		}
+
+		dataset, err = addSortAndPage(dataset, sorts, filter.Page, clientSortColumns)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// SQLite locks the whole database on write, the dialect renders no locking clause
+	if lock {
+		dataset = dataset.ForUpdate(exp.Wait)
+	}
+
+	query, args, err := dataset.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := adapter.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	clients := []model.Client{}
+	for res.Next() {
+		result := model.Client{}
+		err := res.Scan(
+			&result.ID,
+			&result.Name,
+			&result.CreatedAt,
+			&result.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		clients = append(clients, result)
+	}
+
+	return clients, nil
+}
+
+func (adapter *clientAdapter) CountByFilter(ctx context.Context, filter model.ClientFilter) (int, error) {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	dialect := goqu.Dialect(adapter.dialect)
+	dataset := dialect.From(tableClient).
+		Select(goqu.COUNT("*"))
+	dataset = addFilter(dataset, filter)
+
+	query, args, err := dataset.Prepared(true).ToSQL()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = adapter.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (adapter *clientAdapter) DeleteByFilter(ctx context.Context, filter model.ClientFilter) error {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	dialect := goqu.Dialect(adapter.dialect)
+	dataset := dialect.From(tableClient)
+	dataset = addFilter(dataset, filter)
+
+	query, args, err := dataset.Delete().Prepared(true).ToSQL()
+	if err != nil {
+		return err
+	}
+
+	res, err := adapter.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+
+	return nil
+}
+
+func addFilter(dataset *goqu.SelectDataset, filter model.ClientFilter) *goqu.SelectDataset {
+	if filter.IDs != nil {
+		dataset = dataset.Where(goqu.Ex{"id": filter.IDs})
+	}
+
+	if filter.Names != nil {
+		dataset = dataset.Where(goqu.Ex{"name": filter.Names})
+	}
+
+	dataset = addPrefix(dataset, "name", filter.NamePrefix)
+	dataset = addSearch(dataset, "name", filter.NameSearch)
+	dataset = addTimeRange(dataset, "created_at", filter.CreatedAt)
+	dataset = addTimeRange(dataset, "updated_at", filter.UpdatedAt)
+
+	return dataset
+}
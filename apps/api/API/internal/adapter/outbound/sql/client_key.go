@@ -0,0 +1,147 @@
+package sql_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
+	_ "github.com/doug-martin/goqu/v9/dialect/sqlite3"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/deadline"
+)
+
+const tableClientKey = "client_keys"
+
+type clientKeyAdapter struct {
+	db      outbound_port.DatabaseExecutor
+	dialect string
+	timeout time.Duration
+}
+
+func NewClientKeyAdapter(
+	db outbound_port.DatabaseExecutor,
+	dialect string,
+	timeout time.Duration,
+) outbound_port.ClientKeyDatabasePort {
+	return &clientKeyAdapter{
+		db:      db,
+		dialect: dialect,
+		timeout: timeout,
+	}
+}
+
+func (adapter *clientKeyAdapter) Upsert(ctx context.Context, datas []model.ClientKeyInput) error {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	dataset := goqu.Dialect(adapter.dialect).
+		Insert(tableClientKey).
+		Rows(datas).
+		OnConflict(goqu.DoUpdate("client_id, label", goqu.Record{
+			"prefix":     goqu.L("EXCLUDED.prefix"),
+			"salt":       goqu.L("EXCLUDED.salt"),
+			"hash":       goqu.L("EXCLUDED.hash"),
+			"expires_at": goqu.L("EXCLUDED.expires_at"),
+			"updated_at": goqu.L("EXCLUDED.updated_at"),
+		}))
+
+	query, args, err := dataset.Prepared(true).ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = adapter.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return databaseError(err)
+	}
+
+	return nil
+}
+
+func (adapter *clientKeyAdapter) FindByFilter(ctx context.Context, filter model.ClientKeyFilter) ([]model.ClientKey, error) {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	dialect := goqu.Dialect(adapter.dialect)
+	dataset := dialect.From(tableClientKey).
+		Select("id", "client_id", "label", "prefix", "salt", "hash", "expires_at", "created_at", "updated_at")
+	dataset = addClientKeyFilter(dataset, filter)
+
+	query, args, err := dataset.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := adapter.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	clientKeys := []model.ClientKey{}
+	for res.Next() {
+		result := model.ClientKey{}
+		err := res.Scan(
+			&result.ID,
+			&result.ClientID,
+			&result.Label,
+			&result.Prefix,
+			&result.Salt,
+			&result.Hash,
+			&result.ExpiresAt,
+			&result.CreatedAt,
+			&result.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		clientKeys = append(clientKeys, result)
+	}
+
+	return clientKeys, nil
+}
+
+func (adapter *clientKeyAdapter) DeleteByFilter(ctx context.Context, filter model.ClientKeyFilter) error {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	dialect := goqu.Dialect(adapter.dialect)
+	dataset := dialect.From(tableClientKey)
+	dataset = addClientKeyFilter(dataset, filter)
+
+	query, args, err := dataset.Delete().Prepared(true).ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = adapter.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func addClientKeyFilter(dataset *goqu.SelectDataset, filter model.ClientKeyFilter) *goqu.SelectDataset {
+	if filter.IDs != nil {
+		dataset = dataset.Where(goqu.Ex{"id": filter.IDs})
+	}
+
+	if filter.ClientIDs != nil {
+		dataset = dataset.Where(goqu.Ex{"client_id": filter.ClientIDs})
+	}
+
+	if filter.Labels != nil {
+		dataset = dataset.Where(goqu.Ex{"label": filter.Labels})
+	}
+
+	if filter.Prefixes != nil {
+		dataset = dataset.Where(goqu.Ex{"prefix": filter.Prefixes})
+	}
+
+	return dataset
+}
@@ -0,0 +1,61 @@
+package sql_outbound_adapter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	sql_outbound_adapter "prabogo/internal/adapter/outbound/sql"
+	"prabogo/internal/model"
+)
+
+func TestClientKeyAdapter(t *testing.T) {
+	Convey("Test Postgres Client Key Adapter", t, func() {
+		ctx := context.Background()
+		db, mock, err := sqlmock.New()
+		So(err, ShouldBeNil)
+		defer db.Close()
+
+		adapter := sql_outbound_adapter.NewClientKeyAdapter(db, sql_outbound_adapter.DialectPostgres, 0)
+
+		now := time.Now()
+		input := model.ClientKeyInput{ClientID: 1}
+		model.ClientKeyPrepare(&input, "0123456789abcdef")
+
+		Convey("Upsert stores the hash and replaces the key of the same label", func() {
+			mock.ExpectExec(`INSERT INTO "client_keys" .* ON CONFLICT \(client_id, label\) DO UPDATE`).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+
+			err := adapter.Upsert(ctx, []model.ClientKeyInput{input})
+			So(err, ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("FindByFilter looks keys up by prefix", func() {
+			rows := sqlmock.NewRows([]string{"id", "client_id", "label", "prefix", "salt", "hash", "expires_at", "created_at", "updated_at"}).
+				AddRow(1, 1, input.Label, input.Prefix, input.Salt, input.Hash, nil, now, now)
+			mock.ExpectQuery(`SELECT .* FROM "client_keys" WHERE \("prefix" IN \(\$1\)\)`).
+				WithArgs("0123456789ab").
+				WillReturnRows(rows)
+
+			results, err := adapter.FindByFilter(ctx, model.ClientKeyFilter{Prefixes: []string{model.BearerKeyPrefix("0123456789abcdef")}})
+			So(err, ShouldBeNil)
+			So(len(results), ShouldEqual, 1)
+			So(results[0].ExpiresAt, ShouldBeNil)
+			So(results[0].Matches("0123456789abcdef"), ShouldBeTrue)
+			So(results[0].Matches("0123456789abcdeg"), ShouldBeFalse)
+		})
+
+		Convey("DeleteByFilter", func() {
+			mock.ExpectExec(`DELETE FROM "client_keys"`).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			err := adapter.DeleteByFilter(ctx, model.ClientKeyFilter{IDs: []int{1}})
+			So(err, ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,133 @@
+package sql_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	_ "github.com/doug-martin/goqu/v9/dialect/postgres"
+	_ "github.com/doug-martin/goqu/v9/dialect/sqlite3"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/deadline"
+)
+
+const tableClientRole = "client_roles"
+
+type clientRoleAdapter struct {
+	db      outbound_port.DatabaseExecutor
+	dialect string
+	timeout time.Duration
+}
+
+func NewClientRoleAdapter(
+	db outbound_port.DatabaseExecutor,
+	dialect string,
+	timeout time.Duration,
+) outbound_port.ClientRoleDatabasePort {
+	return &clientRoleAdapter{
+		db:      db,
+		dialect: dialect,
+		timeout: timeout,
+	}
+}
+
+func (adapter *clientRoleAdapter) Upsert(ctx context.Context, datas []model.ClientRoleInput) error {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	dataset := goqu.Dialect(adapter.dialect).
+		Insert(tableClientRole).
+		Rows(datas).
+		OnConflict(goqu.DoUpdate("client_id, role", goqu.Record{"updated_at": goqu.L("EXCLUDED.updated_at")}))
+
+	query, args, err := dataset.Prepared(true).ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = adapter.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return databaseError(err)
+	}
+
+	return nil
+}
+
+func (adapter *clientRoleAdapter) FindByFilter(ctx context.Context, filter model.ClientRoleFilter) ([]model.ClientRole, error) {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	dialect := goqu.Dialect(adapter.dialect)
+	dataset := dialect.From(tableClientRole).
+		Select("id", "client_id", "role", "created_at", "updated_at")
+	dataset = addClientRoleFilter(dataset, filter)
+
+	query, args, err := dataset.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := adapter.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	clientRoles := []model.ClientRole{}
+	for res.Next() {
+		result := model.ClientRole{}
+		err := res.Scan(
+			&result.ID,
+			&result.ClientID,
+			&result.Role,
+			&result.CreatedAt,
+			&result.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		clientRoles = append(clientRoles, result)
+	}
+
+	return clientRoles, nil
+}
+
+func (adapter *clientRoleAdapter) DeleteByFilter(ctx context.Context, filter model.ClientRoleFilter) error {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	dialect := goqu.Dialect(adapter.dialect)
+	dataset := dialect.From(tableClientRole)
+	dataset = addClientRoleFilter(dataset, filter)
+
+	query, args, err := dataset.Delete().Prepared(true).ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = adapter.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func addClientRoleFilter(dataset *goqu.SelectDataset, filter model.ClientRoleFilter) *goqu.SelectDataset {
+	if filter.IDs != nil {
+		dataset = dataset.Where(goqu.Ex{"id": filter.IDs})
+	}
+
+	if filter.ClientIDs != nil {
+		dataset = dataset.Where(goqu.Ex{"client_id": filter.ClientIDs})
+	}
+
+	if filter.Roles != nil {
+		dataset = dataset.Where(goqu.Ex{"role": filter.Roles})
+	}
+
+	return dataset
+}
@@ -1,29 +1,30 @@
-package postgres_outbound_adapter_test
+package sql_outbound_adapter_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	. "github.com/smartystreets/goconvey/convey"
 
-	postgres_outbound_adapter "prabogo/internal/adapter/outbound/postgres"
+	sql_outbound_adapter "prabogo/internal/adapter/outbound/sql"
 	"prabogo/internal/model"
 )
 
 func TestClientAdapter(t *testing.T) {
 	Convey("Test Postgres Client Adapter", t, func() {
+		ctx := context.Background()
 		db, mock, err := sqlmock.New()
 		So(err, ShouldBeNil)
 		defer db.Close()
 
-		adapter := postgres_outbound_adapter.NewClientAdapter(db)
+		adapter := sql_outbound_adapter.NewClientAdapter(db, sql_outbound_adapter.DialectPostgres, 0)
 
 		now := time.Now()
 		inputs := []model.ClientInput{
 			{
 				Name:      "Test Client",
-				BearerKey: "test-key",
 				CreatedAt: now,
 				UpdatedAt: now,
 			},
@@ -38,7 +39,7 @@ func TestClientAdapter(t *testing.T) {
 				mock.ExpectExec("INSERT INTO \"clients\"").
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
-				err := adapter.Upsert(inputs)
+				err := adapter.Upsert(ctx, inputs)
 				So(err, ShouldBeNil)
 				So(mock.ExpectationsWereMet(), ShouldBeNil)
 			})
@@ -47,54 +48,69 @@ func TestClientAdapter(t *testing.T) {
 				mock.ExpectExec("INSERT INTO \"clients\"").
 					WillReturnError(sqlmock.ErrCancelled)
 
-				err := adapter.Upsert(inputs)
+				err := adapter.Upsert(ctx, inputs)
 				So(err, ShouldNotBeNil)
 			})
 		})
 
 		Convey("FindByFilter", func() {
 			Convey("Success", func() {
-				rows := sqlmock.NewRows([]string{"id", "name", "bearer_key", "created_at", "updated_at"}).
-					AddRow(1, "Test Client", "test-key", now, now)
+				rows := sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).
+					AddRow(1, "Test Client", now, now)
 
-				mock.ExpectQuery("SELECT \\* FROM \"clients\"").
+				mock.ExpectQuery("SELECT \"id\", \"name\", \"created_at\", \"updated_at\" FROM \"clients\" WHERE \\(\"id\" IN \\(\\$1\\)\\)").
+					WithArgs(1).
 					WillReturnRows(rows)
 
-				results, err := adapter.FindByFilter(filter, false)
+				results, err := adapter.FindByFilter(ctx, filter, false)
 				So(err, ShouldBeNil)
 				So(len(results), ShouldEqual, 1)
 				So(results[0].Name, ShouldEqual, "Test Client")
 				So(mock.ExpectationsWereMet(), ShouldBeNil)
 			})
 
+			Convey("Filter values are sent as arguments", func() {
+				name := "x') OR 1=1; DROP TABLE clients; --"
+				rows := sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"})
+
+				mock.ExpectQuery("FROM \"clients\" WHERE \\(\"name\" IN \\(\\$1\\)\\)$").
+					WithArgs(name).
+					WillReturnRows(rows)
+
+				results, err := adapter.FindByFilter(ctx, model.ClientFilter{Names: []string{name}}, false)
+				So(err, ShouldBeNil)
+				So(len(results), ShouldEqual, 0)
+				So(mock.ExpectationsWereMet(), ShouldBeNil)
+			})
+
 			Convey("With lock", func() {
-				rows := sqlmock.NewRows([]string{"id", "name", "bearer_key", "created_at", "updated_at"}).
-					AddRow(1, "Test Client", "test-key", now, now)
+				rows := sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).
+					AddRow(1, "Test Client", now, now)
 
-				mock.ExpectQuery("SELECT \\* FROM \"clients\"").
+				mock.ExpectQuery("SELECT \"id\", \"name\", \"created_at\", \"updated_at\" FROM \"clients\"").
 					WillReturnRows(rows)
 
-				results, err := adapter.FindByFilter(filter, true)
+				results, err := adapter.FindByFilter(ctx, filter, true)
 				So(err, ShouldBeNil)
 				So(len(results), ShouldEqual, 1)
 				So(mock.ExpectationsWereMet(), ShouldBeNil)
 			})
 
 			Convey("Query error", func() {
-				mock.ExpectQuery("SELECT \\* FROM \"clients\"").
+				mock.ExpectQuery("SELECT \"id\", \"name\", \"created_at\", \"updated_at\" FROM \"clients\"").
 					WillReturnError(sqlmock.ErrCancelled)
 
-				_, err := adapter.FindByFilter(filter, false)
+				_, err := adapter.FindByFilter(ctx, filter, false)
 				So(err, ShouldNotBeNil)
 			})
 
 			Convey("Empty result", func() {
-				rows := sqlmock.NewRows([]string{"id", "name", "bearer_key", "created_at", "updated_at"})
+				rows := sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"})
 
-				mock.ExpectQuery("SELECT \\* FROM \"clients\"").
+				mock.ExpectQuery("SELECT \"id\", \"name\", \"created_at\", \"updated_at\" FROM \"clients\"").
 					WillReturnRows(rows)
 
-				results, err := adapter.FindByFilter(filter, false)
+				results, err := adapter.FindByFilter(ctx, filter, false)
 				So(err, ShouldBeNil)
 				So(len(results), ShouldEqual, 0)
 			})
@@ -106,7 +122,7 @@ func TestClientAdapter(t *testing.T) {
 				mock.ExpectQuery("DELETE FROM \"clients\"").
 					WillReturnRows(rows)
 
-				err := adapter.DeleteByFilter(filter)
+				err := adapter.DeleteByFilter(ctx, filter)
 				So(err, ShouldBeNil)
 				So(mock.ExpectationsWereMet(), ShouldBeNil)
 			})
@@ -115,38 +131,7 @@ func TestClientAdapter(t *testing.T) {
 				mock.ExpectQuery("DELETE FROM \"clients\"").
 					WillReturnError(sqlmock.ErrCancelled)
 
-				err := adapter.DeleteByFilter(filter)
-				So(err, ShouldNotBeNil)
-			})
-		})
-
-		Convey("IsExists", func() {
-			Convey("Exists", func() {
-				rows := sqlmock.NewRows([]string{"id"}).AddRow(1)
-				mock.ExpectQuery("SELECT \"id\" FROM \"clients\"").
-					WillReturnRows(rows)
-
-				exists, err := adapter.IsExists("test-key")
-				So(err, ShouldBeNil)
-				So(exists, ShouldBeTrue)
-				So(mock.ExpectationsWereMet(), ShouldBeNil)
-			})
-
-			Convey("Not exists", func() {
-				rows := sqlmock.NewRows([]string{"id"})
-				mock.ExpectQuery("SELECT \"id\" FROM \"clients\"").
-					WillReturnRows(rows)
-
-				exists, err := adapter.IsExists("nonexistent")
-				So(err, ShouldBeNil)
-				So(exists, ShouldBeFalse)
-			})
-
-			Convey("Query error", func() {
-				mock.ExpectQuery("SELECT \"id\" FROM \"clients\"").
-					WillReturnError(sqlmock.ErrCancelled)
-
-				_, err := adapter.IsExists("test-key")
+				err := adapter.DeleteByFilter(ctx, filter)
 				So(err, ShouldNotBeNil)
 			})
 		})
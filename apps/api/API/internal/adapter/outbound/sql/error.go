@@ -0,0 +1,33 @@
+package sql_outbound_adapter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"prabogo/internal/model"
+)
+
+// pqUniqueViolation is the SQLSTATE of a unique constraint violation
+const pqUniqueViolation = "23505"
+
+// databaseError wraps unique constraint violations of either dialect in model.ErrConflict
+func databaseError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pqUniqueViolation {
+		return fmt.Errorf("%w: %w", model.ErrConflict, err)
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return fmt.Errorf("%w: %w", model.ErrConflict, err)
+		}
+	}
+
+	return err
+}
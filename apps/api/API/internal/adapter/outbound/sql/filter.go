@@ -0,0 +1,132 @@
+package sql_outbound_adapter
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+
+	"prabogo/internal/model"
+)
+
+// columnKind is the Go type a sortable column is compared as when read from a cursor
+type columnKind int
+
+const (
+	kindInt columnKind = iota
+	kindString
+	kindTime
+)
+
+// likeEscaper escapes the LIKE wildcards of user input, the queries declare '\' as escape
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// addTimeRange matches column within r
+func addTimeRange(dataset *goqu.SelectDataset, column string, r model.TimeRange) *goqu.SelectDataset {
+	if r.From != nil {
+		dataset = dataset.Where(goqu.C(column).Gte(*r.From))
+	}
+
+	if r.To != nil {
+		dataset = dataset.Where(goqu.C(column).Lt(*r.To))
+	}
+
+	return dataset
+}
+
+// addPrefix matches column starting with prefix
+func addPrefix(dataset *goqu.SelectDataset, column string, prefix string) *goqu.SelectDataset {
+	if prefix == "" {
+		return dataset
+	}
+
+	return dataset.Where(goqu.L(`? LIKE ? ESCAPE '\'`, goqu.C(column), likeEscaper.Replace(prefix)+"%"))
+}
+
+// addSearch matches column containing search, ignoring case
+func addSearch(dataset *goqu.SelectDataset, column string, search string) *goqu.SelectDataset {
+	if search == "" {
+		return dataset
+	}
+
+	pattern := "%" + likeEscaper.Replace(strings.ToLower(search)) + "%"
+	return dataset.Where(goqu.L(`LOWER(?) LIKE ? ESCAPE '\'`, goqu.C(column), pattern))
+}
+
+// addSortAndPage orders the dataset by sorts, which must be normalized, and limits it to
+// page. A cursor resumes after the row it was made from, comparing the sort columns in order.
+func addSortAndPage(dataset *goqu.SelectDataset, sorts []model.Sort, page model.Pagination, columns map[string]columnKind) (*goqu.SelectDataset, error) {
+	for _, sort := range sorts {
+		if sort.Direction == model.SortDesc {
+			dataset = dataset.OrderAppend(goqu.C(sort.Field).Desc())
+		} else {
+			dataset = dataset.OrderAppend(goqu.C(sort.Field).Asc())
+		}
+	}
+
+	if page.Cursor != "" {
+		values, err := cursorValues(page.Cursor, sorts, columns)
+		if err != nil {
+			return nil, err
+		}
+
+		after := make([]exp.Expression, 0, len(sorts))
+		for i, sort := range sorts {
+			conditions := make([]exp.Expression, 0, i+1)
+			for j := 0; j < i; j++ {
+				conditions = append(conditions, goqu.C(sorts[j].Field).Eq(values[j]))
+			}
+			if sort.Direction == model.SortDesc {
+				conditions = append(conditions, goqu.C(sort.Field).Lt(values[i]))
+			} else {
+				conditions = append(conditions, goqu.C(sort.Field).Gt(values[i]))
+			}
+			after = append(after, goqu.And(conditions...))
+		}
+		dataset = dataset.Where(goqu.Or(after...))
+	} else if page.Offset > 0 {
+		dataset = dataset.Offset(uint(page.Offset))
+	}
+
+	if page.Limit > 0 {
+		dataset = dataset.Limit(uint(page.Limit))
+	}
+
+	return dataset, nil
+}
+
+func cursorValues(cursor string, sorts []model.Sort, columns map[string]columnKind) ([]interface{}, error) {
+	raws, err := model.DecodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if len(raws) != len(sorts) {
+		return nil, model.ErrInvalidCursor
+	}
+
+	values := make([]interface{}, len(raws))
+	for i, raw := range raws {
+		var err error
+		switch columns[sorts[i].Field] {
+		case kindInt:
+			var value int
+			err = json.Unmarshal(raw, &value)
+			values[i] = value
+		case kindString:
+			var value string
+			err = json.Unmarshal(raw, &value)
+			values[i] = value
+		case kindTime:
+			var value time.Time
+			err = json.Unmarshal(raw, &value)
+			values[i] = value
+		}
+		if err != nil {
+			return nil, model.ErrInvalidCursor
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,183 @@
+package sql_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"github.com/doug-martin/goqu/v9"
+	"github.com/doug-martin/goqu/v9/exp"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/deadline"
+)
+
+const tableOutbox = "outbox_messages"
+
+type outboxAdapter struct {
+	db      outbound_port.DatabaseExecutor
+	dialect string
+	timeout time.Duration
+}
+
+func NewOutboxAdapter(
+	db outbound_port.DatabaseExecutor,
+	dialect string,
+	timeout time.Duration,
+) outbound_port.OutboxDatabasePort {
+	return &outboxAdapter{
+		db:      db,
+		dialect: dialect,
+		timeout: timeout,
+	}
+}
+
+func (adapter *outboxAdapter) Insert(ctx context.Context, datas []model.OutboxMessageInput) error {
+	if len(datas) == 0 {
+		return nil
+	}
+
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	dataset := goqu.Dialect(adapter.dialect).
+		Insert(tableOutbox).
+		Rows(datas)
+
+	query, args, err := dataset.Prepared(true).ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = adapter.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return databaseError(err)
+	}
+
+	return nil
+}
+
+func (adapter *outboxAdapter) FindPending(ctx context.Context, now time.Time, limit int) ([]model.OutboxMessage, error) {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	dialect := goqu.Dialect(adapter.dialect)
+	earlier := dialect.From(goqu.T(tableOutbox).As("e")).
+		Select(goqu.L("1")).
+		Where(
+			goqu.I("e.aggregate_type").Eq(goqu.I("o.aggregate_type")),
+			goqu.I("e.aggregate_id").Eq(goqu.I("o.aggregate_id")),
+			goqu.I("e.published_at").IsNull(),
+			goqu.I("e.id").Lt(goqu.I("o.id")),
+		)
+
+	dataset := dialect.From(goqu.T(tableOutbox).As("o")).
+		Select("o.id", "o.topic", "o.aggregate_type", "o.aggregate_id", "o.payload", "o.attempts",
+			"o.last_error", "o.available_at", "o.published_at", "o.created_at").
+		Where(
+			goqu.I("o.published_at").IsNull(),
+			goqu.I("o.available_at").Lte(now),
+			goqu.L("NOT EXISTS ?", earlier),
+		).
+		Order(goqu.I("o.id").Asc()).
+		Limit(uint(limit))
+
+	// SQLite locks the whole database on write and has no locking clause
+	if adapter.dialect == DialectPostgres {
+		dataset = dataset.ForUpdate(exp.SkipLocked)
+	}
+
+	query, args, err := dataset.Prepared(true).ToSQL()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := adapter.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	messages := []model.OutboxMessage{}
+	for res.Next() {
+		result := model.OutboxMessage{}
+		err := res.Scan(
+			&result.ID,
+			&result.Topic,
+			&result.AggregateType,
+			&result.AggregateID,
+			&result.Payload,
+			&result.Attempts,
+			&result.LastError,
+			&result.AvailableAt,
+			&result.PublishedAt,
+			&result.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, result)
+	}
+
+	return messages, res.Err()
+}
+
+func (adapter *outboxAdapter) MarkPublished(ctx context.Context, ids []int, publishedAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return adapter.update(ctx, goqu.Ex{"id": ids}, goqu.Record{"published_at": publishedAt})
+}
+
+func (adapter *outboxAdapter) MarkFailed(ctx context.Context, id int, reason string, availableAt time.Time) error {
+	return adapter.update(ctx, goqu.Ex{"id": id}, goqu.Record{
+		"attempts":     goqu.L("attempts + 1"),
+		"last_error":   reason,
+		"available_at": availableAt,
+	})
+}
+
+func (adapter *outboxAdapter) DeletePublished(ctx context.Context, before time.Time) error {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	dataset := goqu.Dialect(adapter.dialect).
+		Delete(tableOutbox).
+		Where(goqu.C("published_at").Lt(before))
+
+	query, args, err := dataset.Prepared(true).ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = adapter.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (adapter *outboxAdapter) update(ctx context.Context, where goqu.Ex, record goqu.Record) error {
+	ctx, cancel := deadline.WithTimeout(ctx, adapter.timeout)
+	defer cancel()
+
+	dataset := goqu.Dialect(adapter.dialect).
+		Update(tableOutbox).
+		Set(record).
+		Where(where)
+
+	query, args, err := dataset.Prepared(true).ToSQL()
+	if err != nil {
+		return err
+	}
+
+	_, err = adapter.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
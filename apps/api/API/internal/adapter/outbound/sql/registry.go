@@ -0,0 +1,156 @@
+package sql_outbound_adapter
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/deadline"
+)
+
+// Dialects of the SQL databases the adapters can talk to
+const (
+	DialectPostgres = "postgres"
+	DialectSQLite   = "sqlite3"
+)
+
+type adapter struct {
+	db         *sql.DB
+	dbexecutor outbound_port.DatabaseExecutor
+	dialect    string
+	statements *statementCache
+	timeout    time.Duration
+}
+
+// Option configures the database adapters
+type Option func(*adapter)
+
+// WithStatementCache makes queries outside transactions reuse up to size prepared
+// statements, 0 disables the cache
+func WithStatementCache(size int) Option {
+	return func(a *adapter) {
+		if size > 0 {
+			a.statements = newStatementCache(a.db, size)
+		}
+	}
+}
+
+// WithTimeout bounds each query, 0 leaves them unbounded
+func WithTimeout(timeout time.Duration) Option {
+	return func(a *adapter) {
+		a.timeout = timeout
+	}
+}
+
+// Settings are the variables of the adapters, the drivers built on them embed these in theirs
+type Settings struct {
+	// StatementCacheSize is the prepared statements kept per process, 0 disables them
+	StatementCacheSize int           `env:"DATABASE_STATEMENT_CACHE_SIZE" default:"0" validate:"min=0" description:"prepared statements kept per process, 0 disables"`
+	Timeout            time.Duration `env:"DATABASE_TIMEOUT" default:"5s" validate:"min=0" description:"deadline of each query, 0 disables"`
+}
+
+// Options returns the options of the settings
+func (s Settings) Options() []Option {
+	return []Option{WithStatementCache(s.StatementCacheSize), WithTimeout(s.Timeout)}
+}
+
+// NewAdapter creates the adapters for a database of dialect. The SQL databases share
+// the queries, which goqu renders for each dialect.
+func NewAdapter(db *sql.DB, dialect string, options ...Option) outbound_port.DatabasePort {
+	result := &adapter{
+		db:      db,
+		dialect: dialect,
+	}
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+func (s *adapter) DoInTransaction(ctx context.Context, txFunc outbound_port.InTransaction) (out interface{}, err error) {
+	var tx *sql.Tx
+	reg := s
+	if s.dbexecutor == nil {
+		tx, err = s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return
+		}
+		defer func() {
+			if p := recover(); p != nil {
+				_ = tx.Rollback()
+				switch x := p.(type) {
+				case string:
+					err = errors.New(x)
+				case error:
+					err = x
+				default:
+					// Fallback err (per specs, error strings should be lowercase w/o punctuation
+					err = errors.New("unknown panic")
+				}
+			} else if err != nil {
+				xerr := tx.Rollback() // err is non-nil; don't change it
+				if xerr != nil {
+					err = errors.Wrap(err, xerr.Error())
+				}
+			} else {
+				err = tx.Commit() // err is nil; if Commit returns error update err
+			}
+		}()
+		reg = &adapter{
+			db:         s.db,
+			dbexecutor: tx,
+			dialect:    s.dialect,
+			statements: s.statements,
+			timeout:    s.timeout,
+		}
+	}
+	out, err = txFunc(reg)
+	if err != nil {
+		if out != nil {
+			return out, err
+		}
+
+		return nil, err
+	}
+	return
+}
+
+func (s *adapter) Ping(ctx context.Context) error {
+	ctx, cancel := deadline.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var one int
+	return s.executor().QueryRowContext(ctx, "SELECT 1").Scan(&one)
+}
+
+// executor returns the transaction when there is one, otherwise the database behind
+// the statement cache. Transactions run their queries unprepared, as preparing on the
+// pool could wait for the connection the transaction holds.
+func (s *adapter) executor() outbound_port.DatabaseExecutor {
+	if s.dbexecutor != nil {
+		return s.dbexecutor
+	}
+	if s.statements != nil {
+		return &statementExecutor{DatabaseExecutor: s.db, statements: s.statements}
+	}
+	return s.db
+}
+
+func (s *adapter) Client() outbound_port.ClientDatabasePort {
+	return NewClientAdapter(s.executor(), s.dialect, s.timeout)
+}
+
+func (s *adapter) ClientRole() outbound_port.ClientRoleDatabasePort {
+	return NewClientRoleAdapter(s.executor(), s.dialect, s.timeout)
+}
+
+func (s *adapter) ClientKey() outbound_port.ClientKeyDatabasePort {
+	return NewClientKeyAdapter(s.executor(), s.dialect, s.timeout)
+}
+
+func (s *adapter) Outbox() outbound_port.OutboxDatabasePort {
+	return NewOutboxAdapter(s.executor(), s.dialect, s.timeout)
+}
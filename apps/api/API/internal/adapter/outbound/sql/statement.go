@@ -0,0 +1,130 @@
+package sql_outbound_adapter
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+// statementCache keeps prepared statements by query text so the database reuses their
+// plans. When full, the least recently used statement is closed once nobody uses it.
+type statementCache struct {
+	db    *sql.DB
+	size  int
+	mu    sync.Mutex
+	order *list.List
+	items map[string]*list.Element
+}
+
+type statementEntry struct {
+	query   string
+	stmt    *sql.Stmt
+	users   int
+	evicted bool
+}
+
+func newStatementCache(db *sql.DB, size int) *statementCache {
+	return &statementCache{
+		db:    db,
+		size:  size,
+		order: list.New(),
+		items: map[string]*list.Element{},
+	}
+}
+
+// acquire returns the prepared statement of query, the returned release must be called
+// once the statement is no longer needed
+func (c *statementCache) acquire(ctx context.Context, query string) (*sql.Stmt, func(), error) {
+	c.mu.Lock()
+	if element, ok := c.items[query]; ok {
+		c.order.MoveToFront(element)
+		entry := element.Value.(*statementEntry)
+		entry.users++
+		c.mu.Unlock()
+		return entry.stmt, c.releaseFunc(entry), nil
+	}
+	c.mu.Unlock()
+
+	stmt, err := c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Another caller may have prepared the same query meanwhile
+	if element, ok := c.items[query]; ok {
+		_ = stmt.Close()
+		c.order.MoveToFront(element)
+		entry := element.Value.(*statementEntry)
+		entry.users++
+		return entry.stmt, c.releaseFunc(entry), nil
+	}
+
+	entry := &statementEntry{query: query, stmt: stmt, users: 1}
+	c.items[query] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		evicted := oldest.Value.(*statementEntry)
+		delete(c.items, evicted.query)
+		evicted.evicted = true
+		if evicted.users == 0 {
+			_ = evicted.stmt.Close()
+		}
+	}
+
+	return entry.stmt, c.releaseFunc(entry), nil
+}
+
+func (c *statementCache) releaseFunc(entry *statementEntry) func() {
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		entry.users--
+		if entry.evicted && entry.users == 0 {
+			_ = entry.stmt.Close()
+		}
+	}
+}
+
+// statementExecutor runs queries through the statement cache. Rows returned by a
+// statement keep it open until they are closed, even after it has been evicted.
+type statementExecutor struct {
+	outbound_port.DatabaseExecutor
+	statements *statementCache
+}
+
+func (e *statementExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	stmt, release, err := e.statements.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return stmt.ExecContext(ctx, args...)
+}
+
+func (e *statementExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	stmt, release, err := e.statements.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return stmt.QueryContext(ctx, args...)
+}
+
+func (e *statementExecutor) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	stmt, release, err := e.statements.acquire(ctx, query)
+	if err != nil {
+		return e.DatabaseExecutor.QueryRowContext(ctx, query, args...)
+	}
+	defer release()
+
+	return stmt.QueryRowContext(ctx, args...)
+}
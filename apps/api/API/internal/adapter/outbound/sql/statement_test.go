@@ -0,0 +1,77 @@
+package sql_outbound_adapter_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	sql_outbound_adapter "prabogo/internal/adapter/outbound/sql"
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+func TestStatementCache(t *testing.T) {
+	Convey("Test Postgres Statement Cache", t, func() {
+		ctx := context.Background()
+		db, mock, err := sqlmock.New()
+		So(err, ShouldBeNil)
+		defer db.Close()
+
+		columns := []string{"id", "name", "created_at", "updated_at"}
+		filter := model.ClientFilter{IDs: []int{1}}
+
+		Convey("Disabled by default", func() {
+			port := sql_outbound_adapter.NewAdapter(db, sql_outbound_adapter.DialectPostgres)
+			mock.ExpectQuery(`FROM "clients"`).WithArgs(1).WillReturnRows(sqlmock.NewRows(columns))
+
+			_, err := port.Client().FindByFilter(ctx, filter, false)
+			So(err, ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("Reuses the prepared statement of a query", func() {
+			port := sql_outbound_adapter.NewAdapter(db, sql_outbound_adapter.DialectPostgres, sql_outbound_adapter.WithStatementCache(10))
+
+			prepared := mock.ExpectPrepare(`FROM "clients" WHERE \("id" IN \(\$1\)\)`)
+			prepared.ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows(columns))
+			prepared.ExpectQuery().WithArgs(2).WillReturnRows(sqlmock.NewRows(columns))
+
+			_, err := port.Client().FindByFilter(ctx, filter, false)
+			So(err, ShouldBeNil)
+			_, err = port.Client().FindByFilter(ctx, model.ClientFilter{IDs: []int{2}}, false)
+			So(err, ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("Closes the least recently used statement when full", func() {
+			port := sql_outbound_adapter.NewAdapter(db, sql_outbound_adapter.DialectPostgres, sql_outbound_adapter.WithStatementCache(1))
+
+			mock.ExpectPrepare(`WHERE \("id" IN \(\$1\)\)`).WillBeClosed().
+				ExpectQuery().WithArgs(1).WillReturnRows(sqlmock.NewRows(columns))
+			mock.ExpectPrepare(`WHERE \("name" IN \(\$1\)\)`).
+				ExpectQuery().WithArgs("Client").WillReturnRows(sqlmock.NewRows(columns))
+
+			_, err := port.Client().FindByFilter(ctx, filter, false)
+			So(err, ShouldBeNil)
+			_, err = port.Client().FindByFilter(ctx, model.ClientFilter{Names: []string{"Client"}}, false)
+			So(err, ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+
+		Convey("Transactions run unprepared", func() {
+			port := sql_outbound_adapter.NewAdapter(db, sql_outbound_adapter.DialectPostgres, sql_outbound_adapter.WithStatementCache(10))
+
+			mock.ExpectBegin()
+			mock.ExpectQuery(`FROM "clients"`).WithArgs(1).WillReturnRows(sqlmock.NewRows(columns))
+			mock.ExpectCommit()
+
+			_, err := port.DoInTransaction(ctx, func(tx outbound_port.DatabasePort) (interface{}, error) {
+				return tx.Client().FindByFilter(ctx, filter, false)
+			})
+			So(err, ShouldBeNil)
+			So(mock.ExpectationsWereMet(), ShouldBeNil)
+		})
+	})
+}
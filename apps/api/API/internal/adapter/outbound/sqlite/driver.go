@@ -0,0 +1,29 @@
+package sqlite_outbound_adapter
+
+import (
+	"context"
+
+	sql_outbound_adapter "prabogo/internal/adapter/outbound/sql"
+	"prabogo/internal/driver"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/database"
+)
+
+// Settings are the variables the sqlite driver reads
+type Settings struct {
+	// Name is the database file, ":memory:" keeps it in memory
+	Name string `env:"DATABASE_NAME" default:"prabogo.db" description:"database file"`
+	sql_outbound_adapter.Settings
+}
+
+func init() {
+	driver.RegisterDatabase(driver.New("sqlite", "SQLite file or in-memory database, for local runs and tests", func(settings Settings) driver.DatabaseFactory {
+		return func(ctx context.Context) (outbound_port.DatabasePort, func() error, error) {
+			db, err := database.InitDatabase(ctx, "sqlite", database.Config{Name: settings.Name})
+			if err != nil {
+				return nil, nil, err
+			}
+			return NewAdapter(db, settings.Options()...), db.Close, nil
+		}
+	}))
+}
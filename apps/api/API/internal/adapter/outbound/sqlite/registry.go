@@ -0,0 +1,13 @@
+package sqlite_outbound_adapter
+
+import (
+	"database/sql"
+
+	sql_outbound_adapter "prabogo/internal/adapter/outbound/sql"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+// NewAdapter creates the adapters for a SQLite database
+func NewAdapter(db *sql.DB, options ...sql_outbound_adapter.Option) outbound_port.DatabasePort {
+	return sql_outbound_adapter.NewAdapter(db, sql_outbound_adapter.DialectSQLite, options...)
+}
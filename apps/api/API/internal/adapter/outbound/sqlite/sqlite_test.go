@@ -0,0 +1,43 @@
+package sqlite_outbound_adapter_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	sqlite_outbound_adapter "prabogo/internal/adapter/outbound/sqlite"
+	_ "prabogo/internal/migration/sqlite"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/tests/contract"
+	"prabogo/utils/database"
+)
+
+func TestSQLiteAdapter(t *testing.T) {
+	var db *sql.DB
+	t.Cleanup(func() {
+		if db != nil {
+			db.Close()
+		}
+	})
+
+	contract.DatabasePort(t, "sqlite", func() outbound_port.DatabasePort {
+		if db != nil {
+			db.Close()
+		}
+
+		var err error
+		db, err = sql.Open("sqlite", "file::memory:?_pragma=foreign_keys(1)")
+		if err != nil {
+			t.Fatalf("failed to open sqlite: %v", err)
+		}
+		db.SetMaxOpenConns(1)
+
+		if err := database.Migrate(context.Background(), db, "sqlite"); err != nil {
+			t.Fatalf("failed to migrate sqlite: %v", err)
+		}
+
+		return sqlite_outbound_adapter.NewAdapter(db)
+	})
+}
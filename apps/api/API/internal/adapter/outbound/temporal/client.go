@@ -2,22 +2,31 @@ package temporal_outbound_adapter
 
 import (
 	"context"
-	"os"
+	"time"
 
 	"prabogo/internal/model"
 	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/deadline"
 	"prabogo/utils/temporal"
 )
 
-type clientWorkflowAdapter struct{}
+type clientWorkflowAdapter struct {
+	namespace string
+	timeout   time.Duration
+}
 
-func NewClientWorkflowAdapter() outbound_port.ClientWorkflowPort {
-	return &clientWorkflowAdapter{}
+func NewClientWorkflowAdapter(namespace string, timeout time.Duration) outbound_port.ClientWorkflowPort {
+	return &clientWorkflowAdapter{
+		namespace: namespace,
+		timeout:   timeout,
+	}
 }
 
-func (g *clientWorkflowAdapter) StartUpsert(input model.ClientInput) error {
-	namespace := os.Getenv("WORKFLOW_NAMESPACE")
-	_, err := temporal.ExecuteWorkflow(context.Background(), namespace, model.UpsertClientWorkflowName, input)
+func (g *clientWorkflowAdapter) StartUpsert(ctx context.Context, input model.ClientInput) error {
+	ctx, cancel := deadline.WithTimeout(ctx, g.timeout)
+	defer cancel()
+
+	_, err := temporal.ExecuteWorkflow(ctx, g.namespace, model.UpsertClientWorkflowName, input)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,28 @@
+package temporal_outbound_adapter
+
+import (
+	"context"
+	"time"
+
+	"prabogo/internal/driver"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/temporal"
+)
+
+// Settings are the variables the temporal workflow starter reads
+type Settings struct {
+	temporal.Config
+	Timeout time.Duration `env:"WORKFLOW_TIMEOUT" default:"10s" validate:"min=0" description:"deadline of each call, 0 disables"`
+}
+
+func init() {
+	driver.RegisterWorkflow(driver.New("temporal", "Temporal workflow starter", func(settings Settings) driver.WorkflowFactory {
+		return func(ctx context.Context) (outbound_port.WorkflowPort, func() error, error) {
+			temporal.Init(settings.Config)
+			return NewAdapter(settings.Namespace, settings.Timeout), func() error {
+				temporal.Close()
+				return nil
+			}, nil
+		}
+	}))
+}
@@ -1,15 +1,35 @@
 package temporal_outbound_adapter
 
 import (
+	"context"
+	"time"
+
 	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/deadline"
+	"prabogo/utils/temporal"
 )
 
-type adapter struct{}
+type adapter struct {
+	namespace string
+	timeout   time.Duration
+}
+
+// NewAdapter creates the adapters starting workflows in namespace, timeout bounds each
+// call and 0 leaves them unbounded
+func NewAdapter(namespace string, timeout time.Duration) outbound_port.WorkflowPort {
+	return &adapter{
+		namespace: namespace,
+		timeout:   timeout,
+	}
+}
+
+func (a *adapter) Ping(ctx context.Context) error {
+	ctx, cancel := deadline.WithTimeout(ctx, a.timeout)
+	defer cancel()
 
-func NewAdapter() outbound_port.WorkflowPort {
-	return &adapter{}
+	return temporal.Ping(ctx, a.namespace)
 }
 
 func (a *adapter) Client() outbound_port.ClientWorkflowPort {
-	return NewClientWorkflowAdapter()
+	return NewClientWorkflowAdapter(a.namespace, a.timeout)
 }
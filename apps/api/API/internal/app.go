@@ -2,214 +2,425 @@ package internal
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
-	"os/signal"
-	"time"
+	"slices"
+	"strings"
 
-	"github.com/gofiber/fiber/v2"
-	"github.com/joho/godotenv"
 	joonix "github.com/joonix/log"
-	_ "github.com/lib/pq"
 	"github.com/sirupsen/logrus"
 
 	command_inbound_adapter "prabogo/internal/adapter/inbound/command"
-	fiber_inbound_adapter "prabogo/internal/adapter/inbound/fiber"
-	rabbitmq_inbound_adapter "prabogo/internal/adapter/inbound/rabbitmq"
-	temporal_inbound_adapter "prabogo/internal/adapter/inbound/temporal"
-	postgres_outbound_adapter "prabogo/internal/adapter/outbound/postgres"
-	rabbitmq_outbound_adapter "prabogo/internal/adapter/outbound/rabbitmq"
-	redis_outbound_adapter "prabogo/internal/adapter/outbound/redis"
-	temporal_outbound_adapter "prabogo/internal/adapter/outbound/temporal"
+	relay_inbound_adapter "prabogo/internal/adapter/inbound/relay"
+	lazy_outbound_adapter "prabogo/internal/adapter/outbound/lazy"
+	none_outbound_adapter "prabogo/internal/adapter/outbound/none"
+	"prabogo/internal/config"
 	"prabogo/internal/domain"
-	_ "prabogo/internal/migration/postgres"
+	"prabogo/internal/domain/client"
+	"prabogo/internal/domain/health"
+	"prabogo/internal/domain/outbox"
+	"prabogo/internal/driver"
 	outbound_port "prabogo/internal/port/outbound"
 	"prabogo/utils"
 	"prabogo/utils/activity"
-	"prabogo/utils/database"
+	"prabogo/utils/deadline"
+	"prabogo/utils/lifecycle"
 	"prabogo/utils/log"
-	"prabogo/utils/rabbitmq"
-	"prabogo/utils/redis"
 )
 
-var databaseDriverList = []string{"postgres"}
-var httpDriverList = []string{"fiber"}
-var messageDriverList = []string{"rabbitmq"}
-var workflowDriverList = []string{"temporal"}
-var outboundDatabaseDriver string
-var outboundMessageDriver string
-var outboundCacheDriver string
-var outboundWorkflowDriver string
-var inboundHttpDriver string
-var inboundMessageDriver string
-var inboundWorkflowDriver string
+// inboundModes are the run modes, in the order all lists them. relay publishes the
+// outbox, the others are served by an inbound driver.
+var inboundModes = []string{"http", "message", "workflow", "relay"}
+
+// runModePorts are the outbound ports each run mode checks at boot. Commands connect
+// only the ports they call.
+var runModePorts = map[string][]string{
+	"http":     {"database"},
+	"message":  {"database"},
+	"workflow": {"database"},
+	"relay":    {"database", "message"},
+}
+
+// pinger is the part of every outbound port require uses
+type pinger interface {
+	Ping(ctx context.Context) error
+}
 
 type App struct {
-	ctx    context.Context
-	domain domain.Domain
+	ctx       context.Context
+	config    config.Config
+	domain    domain.Domain
+	lifecycle *lifecycle.Manager
+	outbounds map[string]pinger
+	// inbounds are the configured inbound drivers by kind, with their settings read
+	inbounds map[driver.Kind]driver.InboundFactory
+	// driverSettings are the settings of the configured drivers, config print shows them
+	driverSettings []driverSettings
+	// configErrs are the invalid settings and unknown drivers, reported when the app runs
+	configErrs []error
+}
+
+// driverSettings are the settings a driver read
+type driverSettings struct {
+	kind     driver.Kind
+	name     string
+	settings any
 }
 
 func NewApp() *App {
 	ctx := activity.NewContext("init")
 	ctx = activity.WithClientID(ctx, "system")
-	_ = godotenv.Load(".env")
-	configureLogging()
-	outboundDatabaseDriver = os.Getenv("OUTBOUND_DATABASE_DRIVER")
-	outboundMessageDriver = os.Getenv("OUTBOUND_MESSAGE_DRIVER")
-	outboundCacheDriver = os.Getenv("OUTBOUND_CACHE_DRIVER")
-	outboundWorkflowDriver = os.Getenv("OUTBOUND_WORKFLOW_DRIVER")
-	inboundHttpDriver = os.Getenv("INBOUND_HTTP_DRIVER")
-	inboundMessageDriver = os.Getenv("INBOUND_MESSAGE_DRIVER")
-	inboundWorkflowDriver = os.Getenv("INBOUND_WORKFLOW_DRIVER")
-	domain := domain.NewDomain(
-		databaseOutbound(ctx),
-		messageOutbound(ctx),
-		cacheOutbound(ctx),
-		workflowOutbound(ctx),
-	)
+	cfg, err := config.Load()
+	configureLogging(cfg.App.Mode)
+	a := &App{
+		ctx:       ctx,
+		config:    cfg,
+		lifecycle: lifecycle.New(cfg.App.ShutdownTimeout),
+	}
+	if err != nil {
+		a.configErrs = append(a.configErrs, err)
+	}
 
-	return &App{
-		ctx:    ctx,
-		domain: domain,
+	databasePort := a.databaseOutbound()
+	messagePort := a.messageOutbound()
+	cachePort := a.cacheOutbound()
+	workflowPort := a.workflowOutbound()
+	a.inboundDrivers()
+	a.domain = domain.NewDomain(
+		databasePort,
+		messagePort,
+		cachePort,
+		workflowPort,
+		domain.WithClientOptions(client.Options{
+			NegativeTTL:     cfg.Client.NegativeTTL,
+			CoalesceLookups: cfg.Client.CoalesceLookups,
+		}),
+		domain.WithHealthOptions(health.Options{
+			CacheTTL: cfg.Health.CacheTTL,
+			Timeout:  cfg.Health.Timeout,
+		}),
+		domain.WithOutboxOptions(outbox.Options{
+			BatchSize: cfg.Outbox.BatchSize,
+			RetryMin:  cfg.Outbox.RetryMin,
+			RetryMax:  cfg.Outbox.RetryMax,
+			Retention: cfg.Outbox.Retention,
+		}),
+	)
+	a.outbounds = map[string]pinger{
+		"database": databasePort,
+		"message":  messagePort,
+		"cache":    cachePort,
+		"workflow": workflowPort,
 	}
+
+	return a
 }
 
+// Run runs the inbounds of option until they end or the process gets SIGINT or SIGTERM,
+// then stops them and closes the outbound connections within SHUTDOWN_TIMEOUT. option is
+// a run mode, a comma separated list of them or all, which run in one process sharing the
+// outbound connections. The drivers option lists the registered drivers and config print
+// the configuration instead, anything else is a command.
 func (a *App) Run(option string) {
 	switch option {
-	case "http":
-		a.httpInbound()
-	case "message":
-		a.messageInbound()
-	case "workflow":
-		a.workflowInbound()
-	default:
-		a.commandInbound()
+	case "drivers":
+		if err := driver.Print(os.Stdout); err != nil {
+			log.WithContext(a.ctx).Fatalf("failed to list drivers: %v", err)
+		}
+		return
+	case "config":
+		a.configCommand()
+		return
 	}
-}
 
-func databaseOutbound(ctx context.Context) outbound_port.DatabasePort {
-	if !utils.IsInList(databaseDriverList, outboundDatabaseDriver) {
-		log.WithContext(ctx).Fatal("database driver is not supported")
+	if err := errors.Join(a.configErrs...); err != nil {
+		log.WithContext(a.ctx).Fatalf("invalid configuration:\n%v", err)
 		os.Exit(1)
 	}
-	db := database.InitDatabase(ctx, outboundDatabaseDriver)
 
-	switch outboundDatabaseDriver {
-	case "postgres":
-		return postgres_outbound_adapter.NewAdapter(db)
+	ctx, stop := lifecycle.SignalContext(a.ctx)
+	defer stop()
+
+	var err error
+	if modes, ok := runModes(option); ok {
+		a.require(ctx, modes)
+		err = a.serve(ctx, modes)
+	} else {
+		a.commandInbound(ctx)
 	}
-	return nil
-}
 
-func messageOutbound(ctx context.Context) outbound_port.MessagePort {
-	if !utils.IsInList(messageDriverList, outboundMessageDriver) {
-		log.WithContext(ctx).Fatal("message driver is not supported")
+	stop()
+	a.shutdown()
+	if err != nil {
 		os.Exit(1)
 	}
+}
 
-	switch outboundMessageDriver {
-	case "rabbitmq":
-		if err := rabbitmq.InitMessage(); err != nil {
-			log.WithContext(ctx).Fatalf("failed to init rabbitmq: %v", err)
-		}
-		return rabbitmq_outbound_adapter.NewAdapter()
+func (a *App) shutdown() {
+	ctx, cancel := deadline.WithTimeout(a.ctx, a.lifecycle.Timeout())
+	defer cancel()
+
+	if err := a.lifecycle.Shutdown(ctx); err != nil {
+		log.WithContext(ctx).Errorf("failed to shut down: %v", err)
+		return
 	}
-	return nil
+	log.WithContext(ctx).Info("shut down")
 }
 
-func cacheOutbound(ctx context.Context) outbound_port.CachePort {
-	if !utils.IsInList([]string{"redis"}, outboundCacheDriver) {
-		log.WithContext(ctx).Fatal("cache driver is not supported")
-		os.Exit(1)
+func unknownDriver(kind driver.Kind, name string) error {
+	return fmt.Errorf("%s=%q is not a registered driver, the drivers command lists them", kind, name)
+}
+
+// databaseOutbound resolves the database driver and reads its settings, it connects on
+// first use within the context of that call
+func (a *App) databaseOutbound() outbound_port.DatabasePort {
+	name := a.config.Drivers.Database
+	d, ok := driver.Database(name)
+	if !ok {
+		err := unknownDriver(driver.OutboundDatabase, name)
+		a.configErrs = append(a.configErrs, err)
+		return none_outbound_adapter.NewDatabaseAdapter(err)
 	}
 
-	switch outboundCacheDriver {
-	case "redis":
-		redis.InitDatabase()
-		return redis_outbound_adapter.NewAdapter()
+	connect, settings, err := d.Load()
+	a.driverSettings = append(a.driverSettings, driverSettings{driver.OutboundDatabase, name, settings})
+	if err != nil {
+		a.configErrs = append(a.configErrs, err)
+		return none_outbound_adapter.NewDatabaseAdapter(err)
 	}
-	return nil
+
+	port, stop := lazy_outbound_adapter.NewDatabaseAdapter(lazy_outbound_adapter.Connect[outbound_port.DatabasePort](connect))
+	a.lifecycle.OnStop("database", stop)
+	return port
 }
 
-func workflowOutbound(ctx context.Context) outbound_port.WorkflowPort {
-	if !utils.IsInList([]string{"temporal"}, outboundWorkflowDriver) {
-		log.WithContext(ctx).Fatal("workflow driver is not supported")
-		os.Exit(1)
+func (a *App) messageOutbound() outbound_port.MessagePort {
+	name := a.config.Drivers.Message
+	d, ok := driver.Message(name)
+	if !ok {
+		err := unknownDriver(driver.OutboundMessage, name)
+		a.configErrs = append(a.configErrs, err)
+		return none_outbound_adapter.NewMessageAdapter(err)
 	}
 
-	switch outboundWorkflowDriver {
-	case "temporal":
-		return temporal_outbound_adapter.NewAdapter()
+	connect, settings, err := d.Load()
+	a.driverSettings = append(a.driverSettings, driverSettings{driver.OutboundMessage, name, settings})
+	if err != nil {
+		a.configErrs = append(a.configErrs, err)
+		return none_outbound_adapter.NewMessageAdapter(err)
 	}
-	return nil
+
+	port, stop := lazy_outbound_adapter.NewMessageAdapter(lazy_outbound_adapter.Connect[outbound_port.MessagePort](connect))
+	a.lifecycle.OnStop("message", stop)
+	return port
 }
 
-func (a *App) httpInbound() {
-	ctx := a.ctx
-	if !utils.IsInList(httpDriverList, inboundHttpDriver) {
-		log.WithContext(ctx).Fatal("http driver is not supported")
-		os.Exit(1)
+func (a *App) cacheOutbound() outbound_port.CachePort {
+	name := a.config.Drivers.Cache
+	d, ok := driver.Cache(name)
+	if !ok {
+		err := unknownDriver(driver.OutboundCache, name)
+		a.configErrs = append(a.configErrs, err)
+		return none_outbound_adapter.NewCacheAdapter(err)
+	}
+
+	connect, settings, err := d.Load()
+	a.driverSettings = append(a.driverSettings, driverSettings{driver.OutboundCache, name, settings})
+	if err != nil {
+		a.configErrs = append(a.configErrs, err)
+		return none_outbound_adapter.NewCacheAdapter(err)
+	}
+
+	port, stop := lazy_outbound_adapter.NewCacheAdapter(lazy_outbound_adapter.Connect[outbound_port.CachePort](connect))
+	a.lifecycle.OnStop("cache", stop)
+	return port
+}
+
+func (a *App) workflowOutbound() outbound_port.WorkflowPort {
+	name := a.config.Drivers.Workflow
+	d, ok := driver.Workflow(name)
+	if !ok {
+		err := unknownDriver(driver.OutboundWorkflow, name)
+		a.configErrs = append(a.configErrs, err)
+		return none_outbound_adapter.NewWorkflowAdapter(err)
+	}
+
+	connect, settings, err := d.Load()
+	a.driverSettings = append(a.driverSettings, driverSettings{driver.OutboundWorkflow, name, settings})
+	if err != nil {
+		a.configErrs = append(a.configErrs, err)
+		return none_outbound_adapter.NewWorkflowAdapter(err)
+	}
+
+	port, stop := lazy_outbound_adapter.NewWorkflowAdapter(lazy_outbound_adapter.Connect[outbound_port.WorkflowPort](connect))
+	a.lifecycle.OnStop("workflow", stop)
+	return port
+}
+
+// runModes returns the run modes of option, false when it is a command
+func runModes(option string) ([]string, bool) {
+	if option == "all" {
+		return inboundModes, true
+	}
+
+	var modes []string
+	for _, mode := range strings.Split(option, ",") {
+		if !slices.Contains(inboundModes, mode) {
+			return nil, false
+		}
+		if !slices.Contains(modes, mode) {
+			modes = append(modes, mode)
+		}
 	}
+	return modes, true
+}
+
+// require connects the outbound ports the run modes cannot start without, the others
+// connect on first use
+func (a *App) require(ctx context.Context, modes []string) {
+	checked := map[string]bool{}
+	for _, mode := range modes {
+		for _, name := range runModePorts[mode] {
+			if checked[name] {
+				continue
+			}
+			checked[name] = true
+
+			if err := a.outbounds[name].Ping(ctx); err != nil {
+				log.WithContext(ctx).Fatalf("%s mode needs the %s port: %v", mode, name, err)
+				os.Exit(1)
+			}
+		}
+	}
+}
 
-	switch inboundHttpDriver {
-	case "fiber":
-		app := fiber.New()
-		inboundHttpAdapter := fiber_inbound_adapter.NewAdapter(a.domain)
-		fiber_inbound_adapter.InitRoute(ctx, app, inboundHttpAdapter)
+// serve runs the inbounds of modes until ctx is done or one of them fails, which stops the
+// others. A single mode gets the command line arguments, combined modes run every
+// subscriber and worker. Each inbound reports its state to the health domain.
+func (a *App) serve(ctx context.Context, modes []string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, len(modes))
+	for _, mode := range modes {
+		args := os.Args
+		if len(modes) > 1 {
+			args = []string{os.Args[0], mode, "all"}
+		}
+
+		a.domain.Health().Started(mode)
 		go func() {
-			if err := app.Listen(":" + os.Getenv("SERVER_PORT")); err != nil {
-				log.WithContext(ctx).Fatalf("failed to listen and serve: %+v", err)
+			err := a.inbound(ctx, mode, args)
+			if err != nil {
+				err = fmt.Errorf("%s inbound stopped: %w", mode, err)
 			}
+			a.domain.Health().Stopped(mode, err)
+			errs <- err
 		}()
 	}
 
-	ctx, shutdown := context.WithTimeout(ctx, 5*time.Second)
-	defer shutdown()
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, os.Interrupt)
-	<-quit
-
-	log.WithContext(ctx).Info("http server stopped")
+	var result []error
+	for range modes {
+		if err := <-errs; err != nil {
+			log.WithContext(ctx).Error(err)
+			result = append(result, err)
+			cancel()
+		}
+	}
+	return errors.Join(result...)
 }
 
-func (a *App) messageInbound() {
-	ctx := a.ctx
-	if !utils.IsInList(messageDriverList, inboundMessageDriver) {
-		log.WithContext(ctx).Fatal("message driver is not supported")
-		os.Exit(1)
+// inbound serves the domain with the inbound driver of mode until ctx is done
+func (a *App) inbound(ctx context.Context, mode string, args []string) error {
+	if mode == "relay" {
+		log.WithContext(ctx).Info("relay inbound started")
+		return relay_inbound_adapter.InitRoute(ctx, a.domain, a.config.Outbox)
 	}
 
-	switch inboundMessageDriver {
-	case "rabbitmq":
-		inboundMessageAdapter := rabbitmq_inbound_adapter.NewAdapter(a.domain)
-		rabbitmq_inbound_adapter.InitRoute(ctx, os.Args, inboundMessageAdapter)
+	kind, name := a.inboundDriver(mode)
+	serve, ok := a.inbounds[kind]
+	if !ok {
+		return unknownDriver(kind, name)
 	}
+
+	log.WithContext(ctx).Infof("%s inbound %s started", mode, name)
+	return serve(ctx, a.domain, a.lifecycle, args)
 }
 
-func (a *App) commandInbound() {
-	ctx := a.ctx
-	inboundCommandAdapter := command_inbound_adapter.NewAdapter(a.domain)
-	command_inbound_adapter.InitRoute(ctx, os.Args, inboundCommandAdapter)
+// inboundDrivers resolves the configured inbound drivers and reads their settings, so
+// they are checked with the rest of the configuration
+func (a *App) inboundDrivers() {
+	a.inbounds = map[driver.Kind]driver.InboundFactory{}
+	for _, mode := range inboundModes {
+		if mode == "relay" {
+			continue
+		}
+		kind, name := a.inboundDriver(mode)
+		if name == "" {
+			continue
+		}
+
+		d, ok := driver.Inbound(kind, name)
+		if !ok {
+			a.configErrs = append(a.configErrs, unknownDriver(kind, name))
+			continue
+		}
+		serve, settings, err := d.Load()
+		a.driverSettings = append(a.driverSettings, driverSettings{kind, name, settings})
+		if err != nil {
+			a.configErrs = append(a.configErrs, err)
+			continue
+		}
+		a.inbounds[kind] = serve
+	}
 }
 
-func (a *App) workflowInbound() {
-	ctx := a.ctx
-	if !utils.IsInList(workflowDriverList, inboundWorkflowDriver) {
-		log.WithContext(ctx).Fatal("workflow driver is not supported")
-		os.Exit(1)
+// inboundDriver returns the driver kind of mode and the configured driver of that kind
+func (a *App) inboundDriver(mode string) (driver.Kind, string) {
+	switch mode {
+	case "message":
+		return driver.InboundMessage, a.config.Drivers.InboundMessage
+	case "workflow":
+		return driver.InboundWorkflow, a.config.Drivers.InboundWorkflow
+	default:
+		return driver.InboundHttp, a.config.Drivers.InboundHttp
 	}
+}
 
-	switch inboundWorkflowDriver {
-	case "temporal":
-		inboundWorkflowAdapter := temporal_inbound_adapter.NewAdapter(a.domain)
-		temporal_inbound_adapter.InitRoute(ctx, os.Args, inboundWorkflowAdapter)
+// configCommand prints the configuration and the settings of the configured drivers with
+// secrets redacted, then what is invalid in them
+func (a *App) configCommand() {
+	if len(os.Args) < 3 || os.Args[2] != "print" {
+		log.WithContext(a.ctx).Info("config command not found")
+		return
 	}
+
+	if err := a.config.Print(os.Stdout); err != nil {
+		log.WithContext(a.ctx).Fatalf("failed to print configuration: %v", err)
+	}
+	for _, d := range a.driverSettings {
+		fmt.Fprintf(os.Stdout, "\n# %s=%s\n", d.kind, d.name)
+		if err := driver.PrintSettings(os.Stdout, d.settings); err != nil {
+			log.WithContext(a.ctx).Fatalf("failed to print configuration: %v", err)
+		}
+	}
+	if err := errors.Join(a.configErrs...); err != nil {
+		log.WithContext(a.ctx).Errorf("invalid configuration:\n%v", err)
+		os.Exit(1)
+	}
+}
+
+func (a *App) commandInbound(ctx context.Context) {
+	inboundCommandAdapter := command_inbound_adapter.NewAdapter(a.domain)
+	command_inbound_adapter.InitRoute(ctx, os.Args, inboundCommandAdapter)
 }
 
-func configureLogging() {
+func configureLogging(mode string) {
 	logrus.SetLevel(logrus.DebugLevel)
 	logrus.AddHook(utils.LogrusSourceContextHook{})
 
-	if os.Getenv("APP_MODE") != "release" {
+	if mode != "release" {
 		logrus.SetFormatter(&logrus.TextFormatter{ForceColors: true})
 	} else {
 		logrus.SetFormatter(&joonix.FluentdFormatter{})
@@ -0,0 +1,230 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"prabogo/utils/config"
+	"prabogo/utils/ratelimit"
+)
+
+// Config is the configuration of the app, loaded once at startup. The drivers read their
+// own settings, see internal/driver. Every variable may also be read from the file named
+// by <VARIABLE>_FILE.
+type Config struct {
+	App     App
+	Drivers Drivers
+	Client  Client
+	Health  Health
+	Outbox  Outbox
+}
+
+type App struct {
+	// Mode release logs as JSON for Fluentd, anything else logs colored text
+	Mode string `env:"APP_MODE" default:"debug"`
+	// ShutdownTimeout bounds the drain of each inbound, then the stop hooks, 0 waits for them
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" default:"30s" validate:"min=0"`
+}
+
+// Drivers select the adapters of the ports, the drivers command lists them
+type Drivers struct {
+	Database        string `env:"OUTBOUND_DATABASE_DRIVER" default:"none"`
+	Message         string `env:"OUTBOUND_MESSAGE_DRIVER" default:"none"`
+	Cache           string `env:"OUTBOUND_CACHE_DRIVER" default:"none"`
+	Workflow        string `env:"OUTBOUND_WORKFLOW_DRIVER" default:"none"`
+	InboundHttp     string `env:"INBOUND_HTTP_DRIVER"`
+	InboundMessage  string `env:"INBOUND_MESSAGE_DRIVER"`
+	InboundWorkflow string `env:"INBOUND_WORKFLOW_DRIVER"`
+}
+
+// Http are the settings of the HTTP inbound drivers
+type Http struct {
+	Port        string `env:"SERVER_PORT" default:"8000" description:"listen port"`
+	InternalKey string `env:"INTERNAL_KEY" secret:"true" description:"key of the /internal routes"`
+	// RequestTimeout bounds the handling of a request, 0 disables it
+	RequestTimeout time.Duration `env:"HTTP_REQUEST_TIMEOUT" default:"30s" validate:"min=0" description:"deadline of each request, 0 disables"`
+	Auth           Auth
+	// RateLimits are the limits of the route groups by lowercase name, read from
+	// RATE_LIMIT_<GROUP>, RATE_LIMIT_<GROUP>_CLIENTS and RATE_LIMIT_<GROUP>_KEY
+	RateLimits map[string]RateLimit
+}
+
+// Auth selects how the /v1 routes verify bearer tokens, client keys when Driver is empty
+type Auth struct {
+	Driver            string        `env:"AUTH_DRIVER" description:"bearer token verifier of the /v1 routes, jwt or firebase"`
+	JWKSURL           string        `env:"AUTH_JWKS_URL" description:"key set of the jwt verifier"`
+	FirebaseProjectID string        `env:"AUTH_FIREBASE_PROJECT_ID" description:"project of the firebase verifier"`
+	FirebaseCertsURL  string        `env:"AUTH_FIREBASE_CERTS_URL" description:"certificates of the firebase verifier"`
+	RolesClaim        string        `env:"AUTH_ROLES_CLAIM" default:"roles" description:"claim holding the roles"`
+	JWTIssuer         string        `env:"AUTH_JWT_ISSUER" description:"expected issuer"`
+	JWTAudiences      []string      `env:"AUTH_JWT_AUDIENCE" description:"accepted audiences"`
+	JWTScopes         []string      `env:"AUTH_JWT_SCOPES" description:"required scopes"`
+	JWTRequiredClaims []string      `env:"AUTH_JWT_REQUIRED_CLAIMS" description:"claims tokens must have"`
+	JWTLeeway         time.Duration `env:"AUTH_JWT_LEEWAY" validate:"min=0" description:"clock skew allowed"`
+}
+
+// RateLimit throttles a route group, Clients override Limit for some client ids
+type RateLimit struct {
+	Limit   ratelimit.Limit
+	Clients map[string]ratelimit.Limit
+	// Key counts hits per client (the default), ip or route
+	Key string
+}
+
+// Client tunes the bearer key lookups of the client domain
+type Client struct {
+	NegativeTTL     time.Duration `env:"CACHE_NEGATIVE_TTL" default:"30s" validate:"min=0"`
+	CoalesceLookups bool          `env:"CACHE_COALESCE_LOOKUPS" default:"true"`
+}
+
+type Health struct {
+	CacheTTL time.Duration `env:"HEALTH_CACHE_TTL" default:"2s" validate:"min=0"`
+	Timeout  time.Duration `env:"HEALTH_CHECK_TIMEOUT" default:"2s" validate:"min=0"`
+}
+
+// Outbox tunes the relay mode, which publishes the messages written to the outbox
+type Outbox struct {
+	BatchSize    int           `env:"OUTBOX_BATCH_SIZE" default:"100" validate:"min=1"`
+	PollInterval time.Duration `env:"OUTBOX_POLL_INTERVAL" default:"1s" validate:"min=100ms"`
+	RetryMin     time.Duration `env:"OUTBOX_RETRY_MIN" default:"1s" validate:"min=0"`
+	RetryMax     time.Duration `env:"OUTBOX_RETRY_MAX" default:"5m" validate:"min=0"`
+	// Retention keeps published messages for this long, 0 keeps them
+	Retention time.Duration `env:"OUTBOX_RETENTION" default:"168h" validate:"min=0"`
+	// RelayTimeout bounds each batch and each cleanup of the relay, 0 disables it
+	RelayTimeout time.Duration `env:"OUTBOX_RELAY_TIMEOUT" default:"30s" validate:"min=0"`
+}
+
+const rateLimitPrefix = "RATE_LIMIT_"
+
+// Load reads the configuration from the environment and .env, which does not override
+// it. The returned error lists every invalid or missing variable, the valid ones are
+// still set so the config can be printed.
+func Load() (Config, error) {
+	_ = godotenv.Load(".env")
+
+	var cfg Config
+	err := config.Load(&cfg)
+	return cfg, err
+}
+
+// Print writes the configuration, secrets are redacted
+func (c *Config) Print(w io.Writer) error {
+	return config.Print(w, c)
+}
+
+// LoadExtended reads the rate limits and checks the auth settings, see utils/config
+func (h *Http) LoadExtended(environ []string) error {
+	var errs []error
+	var err error
+	h.RateLimits, err = loadRateLimits(environ)
+	errs = append(errs, err)
+
+	switch h.Auth.Driver {
+	case "":
+	case "jwt":
+		if strings.TrimSpace(h.Auth.JWKSURL) == "" {
+			errs = append(errs, errors.New("AUTH_JWKS_URL is required by the jwt auth"))
+		}
+	case "firebase":
+		if strings.TrimSpace(h.Auth.FirebaseProjectID) == "" {
+			errs = append(errs, errors.New("AUTH_FIREBASE_PROJECT_ID is required by the firebase auth"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("AUTH_DRIVER %q is not jwt or firebase", h.Auth.Driver))
+	}
+
+	return errors.Join(errs...)
+}
+
+// PrintExtended writes the rate limits, see utils/config
+func (h *Http) PrintExtended(w io.Writer) error {
+	groups := make([]string, 0, len(h.RateLimits))
+	for group := range h.RateLimits {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		prefix := rateLimitPrefix + strings.ToUpper(group)
+		rateLimit := h.RateLimits[group]
+		if rateLimit.Limit.Requests > 0 {
+			fmt.Fprintf(w, "%s=%s\n", prefix, formatLimit(rateLimit.Limit))
+		}
+		if len(rateLimit.Clients) > 0 {
+			clients := make([]string, 0, len(rateLimit.Clients))
+			for id, limit := range rateLimit.Clients {
+				clients = append(clients, id+"="+formatLimit(limit))
+			}
+			sort.Strings(clients)
+			fmt.Fprintf(w, "%s_CLIENTS=%s\n", prefix, strings.Join(clients, ","))
+		}
+		if rateLimit.Key != "" {
+			fmt.Fprintf(w, "%s_KEY=%s\n", prefix, rateLimit.Key)
+		}
+	}
+	return nil
+}
+
+// loadRateLimits reads the RATE_LIMIT_<GROUP> variables of environ
+func loadRateLimits(environ []string) (map[string]RateLimit, error) {
+	var errs []error
+	rateLimits := map[string]RateLimit{}
+	for _, variable := range environ {
+		name, value, _ := strings.Cut(variable, "=")
+		group, ok := strings.CutPrefix(name, rateLimitPrefix)
+		if !ok || value == "" {
+			continue
+		}
+
+		setting := ""
+		for _, suffix := range []string{"_CLIENTS", "_KEY"} {
+			if trimmed, ok := strings.CutSuffix(group, suffix); ok {
+				group, setting = trimmed, suffix
+				break
+			}
+		}
+		group = strings.ToLower(group)
+		rateLimit := rateLimits[group]
+
+		switch setting {
+		case "_CLIENTS":
+			rateLimit.Clients = map[string]ratelimit.Limit{}
+			for _, override := range config.SplitList(value) {
+				id, clientValue, ok := strings.Cut(override, "=")
+				if !ok {
+					errs = append(errs, fmt.Errorf("%s: %q is not <client>=<limit>", name, override))
+					continue
+				}
+				limit, err := ratelimit.ParseLimit(clientValue)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", name, err))
+					continue
+				}
+				rateLimit.Clients[id] = limit
+			}
+		case "_KEY":
+			if value != "client" && value != "ip" && value != "route" {
+				errs = append(errs, fmt.Errorf("%s %q is not client, ip or route", name, value))
+			}
+			rateLimit.Key = value
+		default:
+			limit, err := ratelimit.ParseLimit(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			}
+			rateLimit.Limit = limit
+		}
+		rateLimits[group] = rateLimit
+	}
+
+	return rateLimits, errors.Join(errs...)
+}
+
+func formatLimit(limit ratelimit.Limit) string {
+	return fmt.Sprintf("%d/%s", limit.Requests, limit.Window)
+}
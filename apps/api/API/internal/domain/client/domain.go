@@ -2,21 +2,37 @@ package client
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"time"
 
 	"github.com/palantir/stacktrace"
-	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
 
 	"prabogo/internal/model"
 	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/validate"
 )
 
 type ClientDomain interface {
 	Upsert(ctx context.Context, inputs []model.ClientInput) ([]model.Client, error)
-	FindByFilter(ctx context.Context, filter model.ClientFilter) ([]model.Client, error)
+	FindByFilter(ctx context.Context, filter model.ClientFilter) ([]model.Client, *model.PageInfo, error)
 	DeleteByFilter(ctx context.Context, filter model.ClientFilter) error
 	PublishUpsert(ctx context.Context, inputs []model.ClientInput) error
 	IsExists(ctx context.Context, bearerKey string) (bool, error)
+	FindByBearerKey(ctx context.Context, bearerKey string) (model.Client, bool, error)
 	StartUpsert(ctx context.Context, input model.ClientInput) error
+	UpsertKey(ctx context.Context, input model.ClientKeyInput) (model.ClientKey, error)
+	FindKeysByFilter(ctx context.Context, filter model.ClientKeyFilter) ([]model.ClientKey, error)
+	DeleteKeysByFilter(ctx context.Context, filter model.ClientKeyFilter) error
+}
+
+// Options tunes the bearer key lookup, the zero value disables both
+type Options struct {
+	// NegativeTTL caches unknown bearer keys for this long
+	NegativeTTL time.Duration
+	// CoalesceLookups shares one database lookup between concurrent misses of the same key
+	CoalesceLookups bool
 }
 
 type clientDomain struct {
@@ -24,6 +40,13 @@ type clientDomain struct {
 	messagePort  outbound_port.MessagePort
 	cachePort    outbound_port.CachePort
 	workflowPort outbound_port.WorkflowPort
+	options      Options
+	lookups      singleflight.Group
+}
+
+type bearerKeyLookup struct {
+	client model.Client
+	found  bool
 }
 
 func NewClientDomain(
@@ -31,75 +54,320 @@ func NewClientDomain(
 	messagePort outbound_port.MessagePort,
 	cachePort outbound_port.CachePort,
 	workflowPort outbound_port.WorkflowPort,
+	options Options,
 ) ClientDomain {
 	return &clientDomain{
 		databasePort: databasePort,
 		messagePort:  messagePort,
 		cachePort:    cachePort,
 		workflowPort: workflowPort,
+		options:      options,
 	}
 }
 
 func (s *clientDomain) Upsert(ctx context.Context, inputs []model.ClientInput) ([]model.Client, error) {
+	if err := model.CheckPermission(ctx, model.PermissionClientWrite); err != nil {
+		return nil, stacktrace.Propagate(err, "upsert client is not permitted")
+	}
+
 	if len(inputs) == 0 {
-		return nil, stacktrace.NewError("inputs is empty")
+		return nil, stacktrace.NewErrorWithCode(model.ErrorCodeValidation, "inputs is empty")
+	}
+
+	if err := validateInput(inputs); err != nil {
+		return nil, err
 	}
 
 	var filter model.ClientFilter
+	rolesByName := map[string][]model.Role{}
 	for i := range inputs {
 		model.ClientPrepare(&inputs[i])
 		filter.Names = append(filter.Names, inputs[i].Name)
+		if inputs[i].Roles != nil {
+			rolesByName[inputs[i].Name] = inputs[i].Roles
+		}
 	}
 
-	databaseClientPort := s.databasePort.Client()
-	err := databaseClientPort.Upsert(inputs)
+	if len(rolesByName) > 0 {
+		if err := model.CheckPermission(ctx, model.PermissionRoleWrite); err != nil {
+			return nil, stacktrace.Propagate(err, "assign client roles is not permitted")
+		}
+		for _, roles := range rolesByName {
+			for _, role := range roles {
+				if !role.IsValid() {
+					return nil, stacktrace.NewErrorWithCode(model.ErrorCodeValidation, "role %s is invalid", role)
+				}
+			}
+		}
+	}
+
+	out, err := s.databasePort.DoInTransaction(ctx, func(tx outbound_port.DatabasePort) (interface{}, error) {
+		return s.upsert(ctx, tx, inputs, filter, rolesByName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	results := out.([]model.Client)
+
+	// Evicted once committed. A concurrent lookup that read the replaced rows before the
+	// commit cannot cache them afterwards, as the eviction changes the cache version.
+	err = s.invalidate(ctx, clientIDs(results))
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// upsert stores the clients, their roles and their first keys in tx, so a failed step
+// leaves no client without its roles
+func (s *clientDomain) upsert(
+	ctx context.Context,
+	tx outbound_port.DatabasePort,
+	inputs []model.ClientInput,
+	filter model.ClientFilter,
+	rolesByName map[string][]model.Role,
+) ([]model.Client, error) {
+	databaseClientPort := tx.Client()
+	err := databaseClientPort.Upsert(ctx, inputs)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "upsert client error")
 	}
 
-	results, err := databaseClientPort.FindByFilter(filter, true)
+	results, err := databaseClientPort.FindByFilter(ctx, filter, true)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "find client by filter error")
 	}
 
+	if len(rolesByName) > 0 {
+		err = s.replaceRoles(ctx, tx, results, rolesByName)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "replace client roles error")
+		}
+	}
+
+	err = s.issueKeys(ctx, tx, results)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "issue client keys error")
+	}
+
 	return results, nil
 }
 
-func (s *clientDomain) FindByFilter(ctx context.Context, filter model.ClientFilter) ([]model.Client, error) {
+// invalidate evicts the cached clients so the next request reloads them
+func (s *clientDomain) invalidate(ctx context.Context, clientIDs []int) error {
+	if len(clientIDs) == 0 {
+		return nil
+	}
+
+	err := s.cachePort.Client().DeleteByClientIDs(ctx, clientIDs)
+	if err != nil {
+		return stacktrace.Propagate(err, "delete client from cache error")
+	}
+
+	return nil
+}
+
+func clientIDs(clients []model.Client) []int {
+	ids := make([]int, 0, len(clients))
+	for _, client := range clients {
+		ids = append(ids, client.ID)
+	}
+	return ids
+}
+
+// issueKeys creates a default key for the clients that have none and sets it on the
+// result, it is the only time the key is returned
+func (s *clientDomain) issueKeys(ctx context.Context, tx outbound_port.DatabasePort, clients []model.Client) error {
+	if len(clients) == 0 {
+		return nil
+	}
+
+	var filter model.ClientKeyFilter
+	for i := range clients {
+		filter.ClientIDs = append(filter.ClientIDs, clients[i].ID)
+	}
+
+	databaseClientKeyPort := tx.ClientKey()
+	clientKeys, err := databaseClientKeyPort.FindByFilter(ctx, filter)
+	if err != nil {
+		return stacktrace.Propagate(err, "find client key by filter error")
+	}
+
+	hasKey := map[int]bool{}
+	for _, clientKey := range clientKeys {
+		hasKey[clientKey.ClientID] = true
+	}
+
+	var inputs []model.ClientKeyInput
+	for i := range clients {
+		if hasKey[clients[i].ID] {
+			continue
+		}
+
+		clients[i].BearerKey = model.GenerateBearerKey()
+		input := model.ClientKeyInput{ClientID: clients[i].ID}
+		model.ClientKeyPrepare(&input, clients[i].BearerKey)
+		inputs = append(inputs, input)
+	}
+
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	err = databaseClientKeyPort.Upsert(ctx, inputs)
+	if err != nil {
+		return stacktrace.Propagate(err, "upsert client key error")
+	}
+
+	return nil
+}
+
+// replaceRoles replaces the stored roles of the clients that were upserted with roles
+func (s *clientDomain) replaceRoles(ctx context.Context, tx outbound_port.DatabasePort, clients []model.Client, rolesByName map[string][]model.Role) error {
+	var filter model.ClientRoleFilter
+	var inputs []model.ClientRoleInput
+	for i := range clients {
+		roles, ok := rolesByName[clients[i].Name]
+		if !ok {
+			continue
+		}
+
+		clients[i].Roles = roles
+		filter.ClientIDs = append(filter.ClientIDs, clients[i].ID)
+		for _, role := range roles {
+			input := model.ClientRoleInput{
+				ClientID: clients[i].ID,
+				Role:     role,
+			}
+			model.ClientRolePrepare(&input)
+			inputs = append(inputs, input)
+		}
+	}
+
 	if filter.IsEmpty() {
-		return nil, stacktrace.NewError("filter is empty")
+		return nil
+	}
+
+	databaseClientRolePort := tx.ClientRole()
+	err := databaseClientRolePort.DeleteByFilter(ctx, filter)
+	if err != nil {
+		return stacktrace.Propagate(err, "delete client role by filter error")
+	}
+
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	err = databaseClientRolePort.Upsert(ctx, inputs)
+	if err != nil {
+		return stacktrace.Propagate(err, "upsert client role error")
+	}
+
+	return nil
+}
+
+// FindByFilter returns the matching clients. When filter asks for a page, the page info
+// carries the total count and the cursor of the next page, if any. A paged filter may be empty.
+func (s *clientDomain) FindByFilter(ctx context.Context, filter model.ClientFilter) ([]model.Client, *model.PageInfo, error) {
+	if err := model.CheckPermission(ctx, model.PermissionClientRead); err != nil {
+		return nil, nil, stacktrace.Propagate(err, "find client is not permitted")
+	}
+
+	if err := validateInput(&filter); err != nil {
+		return nil, nil, err
+	}
+
+	if filter.IsEmpty() && filter.Page.Limit == 0 {
+		return nil, nil, stacktrace.NewErrorWithCode(model.ErrorCodeValidation, "filter is empty")
+	}
+
+	sorts, err := model.NormalizeSort(filter.Sort, model.ClientSortFields...)
+	if err != nil {
+		return nil, nil, stacktrace.PropagateWithCode(err, model.ErrorCodeValidation, "invalid sort")
 	}
 
 	databaseClientPort := s.databasePort.Client()
-	results, err := databaseClientPort.FindByFilter(filter, false)
+	if filter.Page.Limit == 0 {
+		results, err := databaseClientPort.FindByFilter(ctx, filter, false)
+		if err != nil {
+			return nil, nil, stacktrace.Propagate(err, "find client by filter error")
+		}
+
+		return results, nil, nil
+	}
+
+	page := &model.PageInfo{
+		Limit:  min(filter.Page.Limit, model.MaxPageLimit),
+		Offset: filter.Page.Offset,
+	}
+	if filter.Page.Cursor != "" {
+		page.Offset = 0
+	}
+
+	page.Total, err = databaseClientPort.CountByFilter(ctx, filter)
 	if err != nil {
-		return nil, stacktrace.Propagate(err, "find client by filter error")
+		return nil, nil, stacktrace.Propagate(err, "count client by filter error")
 	}
 
-	return results, nil
+	// One extra row tells whether there is a next page
+	filter.Sort = sorts
+	filter.Page.Limit = page.Limit + 1
+	results, err := databaseClientPort.FindByFilter(ctx, filter, false)
+	if err != nil {
+		return nil, nil, stacktrace.Propagate(err, "find client by filter error")
+	}
+
+	if len(results) > page.Limit {
+		results = results[:page.Limit]
+		page.NextCursor, err = model.ClientCursor(results[len(results)-1], sorts)
+		if err != nil {
+			return nil, nil, stacktrace.Propagate(err, "encode cursor error")
+		}
+	}
+
+	return results, page, nil
 }
 
 func (s *clientDomain) DeleteByFilter(ctx context.Context, filter model.ClientFilter) error {
+	if err := model.CheckPermission(ctx, model.PermissionClientDelete); err != nil {
+		return stacktrace.Propagate(err, "delete client is not permitted")
+	}
+
 	if filter.IsEmpty() {
-		return stacktrace.NewError("filter is empty")
+		return stacktrace.NewErrorWithCode(model.ErrorCodeValidation, "filter is empty")
 	}
 
 	databaseClientPort := s.databasePort.Client()
-	err := databaseClientPort.DeleteByFilter(filter)
+	clients, err := databaseClientPort.FindByFilter(ctx, filter, false)
+	if err != nil {
+		return stacktrace.Propagate(err, "find client by filter error")
+	}
+
+	err = databaseClientPort.DeleteByFilter(ctx, filter)
 	if err != nil {
 		return stacktrace.Propagate(err, "delete client by filter error")
 	}
 
-	return nil
+	return s.invalidate(ctx, clientIDs(clients))
 }
 
 func (s *clientDomain) PublishUpsert(ctx context.Context, inputs []model.ClientInput) error {
+	if err := model.CheckPermission(ctx, model.PermissionClientWrite); err != nil {
+		return stacktrace.Propagate(err, "publish upsert client is not permitted")
+	}
+
 	if len(inputs) == 0 {
-		return stacktrace.NewError("inputs is empty")
+		return stacktrace.NewErrorWithCode(model.ErrorCodeValidation, "inputs is empty")
+	}
+
+	if err := validateInput(inputs); err != nil {
+		return err
 	}
 
-	messageClientPort := s.messagePort.Client()
-	err := messageClientPort.PublishUpsert(inputs)
+	_, err := s.databasePort.DoInTransaction(ctx, func(tx outbound_port.DatabasePort) (interface{}, error) {
+		return nil, s.publishUpsert(ctx, tx, inputs)
+	})
 	if err != nil {
 		return stacktrace.Propagate(err, "publish upsert client error")
 	}
@@ -107,46 +375,263 @@ func (s *clientDomain) PublishUpsert(ctx context.Context, inputs []model.ClientI
 	return nil
 }
 
+// publishUpsert writes an upsert message per input to the outbox of tx, the relay
+// publishes them once tx commits
+func (s *clientDomain) publishUpsert(ctx context.Context, tx outbound_port.DatabasePort, inputs []model.ClientInput) error {
+	messages := make([]model.OutboxMessageInput, 0, len(inputs))
+	for _, input := range inputs {
+		message, err := model.NewOutboxMessage(model.UpsertClientMessage, model.ClientAggregate, input.Name, []model.ClientInput{input})
+		if err != nil {
+			return stacktrace.Propagate(err, "encode upsert client message error")
+		}
+		messages = append(messages, message)
+	}
+
+	err := tx.Outbox().Insert(ctx, messages)
+	if err != nil {
+		return stacktrace.Propagate(err, "insert outbox message error")
+	}
+
+	return nil
+}
+
 func (s *clientDomain) IsExists(ctx context.Context, bearerKey string) (bool, error) {
+	_, exists, err := s.FindByBearerKey(ctx, bearerKey)
+	return exists, err
+}
+
+func (s *clientDomain) FindByBearerKey(ctx context.Context, bearerKey string) (model.Client, bool, error) {
 	if bearerKey == "" {
-		return false, stacktrace.NewError("bearerKey is empty")
+		return model.Client{}, false, stacktrace.NewErrorWithCode(model.ErrorCodeUnauthorized, "bearerKey is empty")
 	}
 
-	var exists bool
+	now := time.Now()
 	cacheClientPort := s.cachePort.Client()
-	_, err := cacheClientPort.Get(bearerKey)
-	if err != nil {
-		if err == redis.Nil {
-			databaseClientPort := s.databasePort.Client()
-			exists, err = databaseClientPort.IsExists(bearerKey)
+	client, err := cacheClientPort.Get(ctx, bearerKey)
+	if err == nil {
+		if client.KeyExpiresAt != nil && !now.Before(*client.KeyExpiresAt) {
+			err = cacheClientPort.Delete(ctx, bearerKey)
 			if err != nil {
-				return false, stacktrace.Propagate(err, "check if client exists error")
+				return model.Client{}, false, stacktrace.Propagate(err, "delete client from cache error")
 			}
+			return model.Client{}, false, nil
+		}
+		return client, true, nil
+	}
+	if errors.Is(err, outbound_port.ErrClientUnknown) {
+		return model.Client{}, false, nil
+	}
+	if !errors.Is(err, outbound_port.ErrCacheMiss) {
+		return model.Client{}, false, stacktrace.Propagate(err, "get client from cache error")
+	}
 
-			if exists {
-				client, findErr := databaseClientPort.FindByFilter(model.ClientFilter{BearerKeys: []string{bearerKey}}, false)
-				if findErr != nil {
-					return false, stacktrace.Propagate(findErr, "find client by filter error")
-				}
+	if !s.options.CoalesceLookups {
+		return s.lookupBearerKey(ctx, bearerKey)
+	}
 
-				if len(client) > 0 {
-					setErr := cacheClientPort.Set(client[0])
-					if setErr != nil {
-						return false, stacktrace.Propagate(setErr, "set client to cache error")
-					}
-				}
-			}
-		} else {
-			return false, stacktrace.Propagate(err, "get client from cache error")
+	// The shared lookup must not fail for every caller when the first one goes away
+	lookupCtx := context.WithoutCancel(ctx)
+	result, err, _ := s.lookups.Do(bearerKey, func() (interface{}, error) {
+		client, found, err := s.lookupBearerKey(lookupCtx, bearerKey)
+		return bearerKeyLookup{client: client, found: found}, err
+	})
+	if err != nil {
+		return model.Client{}, false, err
+	}
+
+	lookup := result.(bearerKeyLookup)
+	return lookup.client, lookup.found, nil
+}
+
+// lookupBearerKey loads the client of a bearer key from the database and caches
+// the result, unknown keys are cached for NegativeTTL
+func (s *clientDomain) lookupBearerKey(ctx context.Context, bearerKey string) (model.Client, bool, error) {
+	now := time.Now()
+	cacheClientPort := s.cachePort.Client()
+
+	// Read before the database, so an eviction in between makes Set skip the result
+	version, err := cacheClientPort.Version(ctx)
+	if err != nil {
+		return model.Client{}, false, stacktrace.Propagate(err, "get client cache version error")
+	}
+
+	clientKeys, err := s.databasePort.ClientKey().FindByFilter(ctx, model.ClientKeyFilter{Prefixes: []string{model.BearerKeyPrefix(bearerKey)}})
+	if err != nil {
+		return model.Client{}, false, stacktrace.Propagate(err, "find client key by filter error")
+	}
+
+	var clientKey *model.ClientKey
+	for i := range clientKeys {
+		if clientKeys[i].Matches(bearerKey) && !clientKeys[i].IsExpired(now) {
+			clientKey = &clientKeys[i]
+			break
 		}
-	} else {
-		exists = true
 	}
 
-	return exists, nil
+	if clientKey == nil {
+		return model.Client{}, false, s.setUnknown(ctx, bearerKey)
+	}
+
+	clients, err := s.databasePort.Client().FindByFilter(ctx, model.ClientFilter{IDs: []int{clientKey.ClientID}}, false)
+	if err != nil {
+		return model.Client{}, false, stacktrace.Propagate(err, "find client by filter error")
+	}
+
+	if len(clients) == 0 {
+		return model.Client{}, false, s.setUnknown(ctx, bearerKey)
+	}
+
+	clientRoles, err := s.databasePort.ClientRole().FindByFilter(ctx, model.ClientRoleFilter{ClientIDs: []int{clients[0].ID}})
+	if err != nil {
+		return model.Client{}, false, stacktrace.Propagate(err, "find client role by filter error")
+	}
+
+	clients[0].Roles = []model.Role{}
+	for _, clientRole := range clientRoles {
+		clients[0].Roles = append(clients[0].Roles, clientRole.Role)
+	}
+	clients[0].KeyExpiresAt = clientKey.ExpiresAt
+
+	err = cacheClientPort.Set(ctx, bearerKey, clients[0], version)
+	if err != nil {
+		return model.Client{}, false, stacktrace.Propagate(err, "set client to cache error")
+	}
+
+	return clients[0], true, nil
+}
+
+func (s *clientDomain) setUnknown(ctx context.Context, bearerKey string) error {
+	if s.options.NegativeTTL <= 0 {
+		return nil
+	}
+
+	err := s.cachePort.Client().SetUnknown(ctx, bearerKey, s.options.NegativeTTL)
+	if err != nil {
+		return stacktrace.Propagate(err, "set unknown client to cache error")
+	}
+
+	return nil
 }
 
 func (s *clientDomain) StartUpsert(ctx context.Context, input model.ClientInput) error {
+	if err := model.CheckPermission(ctx, model.PermissionClientWrite); err != nil {
+		return stacktrace.Propagate(err, "start upsert client is not permitted")
+	}
+
+	if err := validateInput(&input); err != nil {
+		return err
+	}
+
 	workflowClientPort := s.workflowPort.Client()
-	return workflowClientPort.StartUpsert(input)
+	return workflowClientPort.StartUpsert(ctx, input)
+}
+
+func (s *clientDomain) UpsertKey(ctx context.Context, input model.ClientKeyInput) (model.ClientKey, error) {
+	if err := model.CheckPermission(ctx, model.PermissionClientWrite); err != nil {
+		return model.ClientKey{}, stacktrace.Propagate(err, "upsert client key is not permitted")
+	}
+
+	if err := validateInput(&input); err != nil {
+		return model.ClientKey{}, err
+	}
+
+	if input.ExpiresAt != nil && !time.Now().Before(*input.ExpiresAt) {
+		return model.ClientKey{}, stacktrace.NewErrorWithCode(model.ErrorCodeValidation, "expires at is in the past")
+	}
+
+	clients, err := s.databasePort.Client().FindByFilter(ctx, model.ClientFilter{IDs: []int{input.ClientID}}, false)
+	if err != nil {
+		return model.ClientKey{}, stacktrace.Propagate(err, "find client by filter error")
+	}
+
+	if len(clients) == 0 {
+		return model.ClientKey{}, stacktrace.NewErrorWithCode(model.ErrorCodeNotFound, "client %d does not exist", input.ClientID)
+	}
+
+	bearerKey := model.GenerateBearerKey()
+	model.ClientKeyPrepare(&input, bearerKey)
+
+	databaseClientKeyPort := s.databasePort.ClientKey()
+	err = databaseClientKeyPort.Upsert(ctx, []model.ClientKeyInput{input})
+	if err != nil {
+		return model.ClientKey{}, stacktrace.Propagate(err, "upsert client key error")
+	}
+
+	results, err := databaseClientKeyPort.FindByFilter(ctx, model.ClientKeyFilter{
+		ClientIDs: []int{input.ClientID},
+		Labels:    []string{input.Label},
+	})
+	if err != nil {
+		return model.ClientKey{}, stacktrace.Propagate(err, "find client key by filter error")
+	}
+
+	if len(results) == 0 {
+		return model.ClientKey{}, stacktrace.NewError("client key was not stored")
+	}
+
+	// A key replaced under the same label must stop working right away
+	err = s.invalidate(ctx, []int{input.ClientID})
+	if err != nil {
+		return model.ClientKey{}, err
+	}
+
+	results[0].BearerKey = bearerKey
+	return results[0], nil
+}
+
+func (s *clientDomain) FindKeysByFilter(ctx context.Context, filter model.ClientKeyFilter) ([]model.ClientKey, error) {
+	if err := model.CheckPermission(ctx, model.PermissionClientRead); err != nil {
+		return nil, stacktrace.Propagate(err, "find client key is not permitted")
+	}
+
+	if filter.IsEmpty() {
+		return nil, stacktrace.NewErrorWithCode(model.ErrorCodeValidation, "filter is empty")
+	}
+
+	results, err := s.databasePort.ClientKey().FindByFilter(ctx, filter)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "find client key by filter error")
+	}
+
+	return results, nil
+}
+
+func (s *clientDomain) DeleteKeysByFilter(ctx context.Context, filter model.ClientKeyFilter) error {
+	if err := model.CheckPermission(ctx, model.PermissionClientWrite); err != nil {
+		return stacktrace.Propagate(err, "delete client key is not permitted")
+	}
+
+	if filter.IsEmpty() {
+		return stacktrace.NewErrorWithCode(model.ErrorCodeValidation, "filter is empty")
+	}
+
+	databaseClientKeyPort := s.databasePort.ClientKey()
+	clientKeys, err := databaseClientKeyPort.FindByFilter(ctx, filter)
+	if err != nil {
+		return stacktrace.Propagate(err, "find client key by filter error")
+	}
+
+	err = databaseClientKeyPort.DeleteByFilter(ctx, filter)
+	if err != nil {
+		return stacktrace.Propagate(err, "delete client key by filter error")
+	}
+
+	var ids []int
+	for _, clientKey := range clientKeys {
+		if !slices.Contains(ids, clientKey.ClientID) {
+			ids = append(ids, clientKey.ClientID)
+		}
+	}
+
+	return s.invalidate(ctx, ids)
+}
+
+// validateInput strips the server-controlled fields of caller input and checks the rest
+// against its validate tags
+func validateInput(input any) error {
+	validate.Strip(input)
+	if err := validate.Struct(input); err != nil {
+		return stacktrace.PropagateWithCode(err, model.ErrorCodeValidation, "invalid input")
+	}
+	return nil
 }
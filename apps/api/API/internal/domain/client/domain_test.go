@@ -3,16 +3,20 @@ package client_test
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
-	"github.com/redis/go-redis/v9"
+	"github.com/palantir/stacktrace"
 	. "github.com/smartystreets/goconvey/convey"
 
 	"prabogo/internal/domain"
+	"prabogo/internal/domain/client"
 	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
 	mock_outbound_port "prabogo/tests/mocks/port"
+	"prabogo/utils/activity"
 )
 
 func TestClient(t *testing.T) {
@@ -30,10 +34,21 @@ func TestClient(t *testing.T) {
 		mockClientMessagePort := mock_outbound_port.NewMockClientMessagePort(mockCtrl)
 		mockClientCachePort := mock_outbound_port.NewMockClientCachePort(mockCtrl)
 		mockClientWorkflowPort := mock_outbound_port.NewMockClientWorkflowPort(mockCtrl)
+		mockClientRoleDatabasePort := mock_outbound_port.NewMockClientRoleDatabasePort(mockCtrl)
+		mockClientKeyDatabasePort := mock_outbound_port.NewMockClientKeyDatabasePort(mockCtrl)
+		mockOutboxDatabasePort := mock_outbound_port.NewMockOutboxDatabasePort(mockCtrl)
 
 		mockDatabasePort.EXPECT().Client().Return(mockClientDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().ClientRole().Return(mockClientRoleDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().ClientKey().Return(mockClientKeyDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().Outbox().Return(mockOutboxDatabasePort).AnyTimes()
+		mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, txFunc outbound_port.InTransaction) (interface{}, error) {
+				return txFunc(mockDatabasePort)
+			}).AnyTimes()
 		mockMessagePort.EXPECT().Client().Return(mockClientMessagePort).AnyTimes()
 		mockCachePort.EXPECT().Client().Return(mockClientCachePort).AnyTimes()
+		mockClientCachePort.EXPECT().Version(gomock.Any()).Return(int64(3), nil).AnyTimes()
 		mockWorkflowPort.EXPECT().Client().Return(mockClientWorkflowPort).AnyTimes()
 
 		clientDomain := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockWorkflowPort)
@@ -49,7 +64,6 @@ func TestClient(t *testing.T) {
 				ID: 1,
 				ClientInput: model.ClientInput{
 					Name:      "Test Client",
-					BearerKey: "test-bearer-key",
 					UpdatedAt: time.Now(),
 					CreatedAt: time.Now(),
 				},
@@ -57,11 +71,14 @@ func TestClient(t *testing.T) {
 		}
 
 		filter := model.ClientFilter{
-			BearerKeys: []string{"test-bearer-key"},
-			IDs:        []int{1},
-			Names:      []string{"Test Client"},
+			IDs:   []int{1},
+			Names: []string{"Test Client"},
 		}
 
+		bearerKey := model.GenerateBearerKey()
+		clientKey := model.ClientKey{ID: 1, ClientKeyInput: model.ClientKeyInput{ClientID: 1}}
+		model.ClientKeyPrepare(&clientKey.ClientKeyInput, bearerKey)
+
 		Convey("Upsert", func() {
 			Convey("Input is empty", func() {
 				_, err := clientDomain.Client().Upsert(context.Background(), []model.ClientInput{})
@@ -69,23 +86,81 @@ func TestClient(t *testing.T) {
 			})
 
 			Convey("Database client upsert error", func() {
-				mockClientDatabasePort.EXPECT().Upsert(gomock.Any()).Return(errors.New("error")).Times(1)
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(errors.New("error")).Times(1)
 
 				_, err := clientDomain.Client().Upsert(context.Background(), inputs)
 				So(err, ShouldNotBeNil)
 			})
 
 			Convey("Database client find by filter error", func() {
-				mockClientDatabasePort.EXPECT().Upsert(gomock.Any()).Return(nil).Times(1)
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
+
+				_, err := clientDomain.Client().Upsert(context.Background(), inputs)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Permission denied", func() {
+				ctx := activity.WithRoles(context.Background(), []string{string(model.RoleStaff)})
+
+				_, err := clientDomain.Client().Upsert(ctx, inputs)
+				So(stacktrace.RootCause(err), ShouldEqual, model.ErrPermissionDenied)
+			})
+
+			Convey("Invalid role", func() {
+				inputs[0].Roles = []model.Role{"landlord"}
+
+				_, err := clientDomain.Client().Upsert(context.Background(), inputs)
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success with roles", func() {
+				inputs[0].Roles = []model.Role{model.RoleManager}
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientRoleDatabasePort.EXPECT().DeleteByFilter(gomock.Any(), model.ClientRoleFilter{ClientIDs: []int{1}}).Return(nil).Times(1)
+				mockClientRoleDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return([]model.ClientKey{clientKey}, nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteByClientIDs(gomock.Any(), []int{1}).Return(nil).Times(1)
+
+				ctx := activity.WithRoles(context.Background(), []string{string(model.RoleOwner)})
+				results, err := clientDomain.Client().Upsert(ctx, inputs)
+				So(err, ShouldBeNil)
+				So(results[0].Roles, ShouldResemble, []model.Role{model.RoleManager})
+			})
+
+			Convey("Success issues a key to a new client", func() {
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), model.ClientKeyFilter{ClientIDs: []int{1}}).Return(nil, nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, datas []model.ClientKeyInput) error {
+					So(datas, ShouldHaveLength, 1)
+					So(datas[0].Label, ShouldEqual, model.DefaultClientKeyLabel)
+					So(datas[0].Hash, ShouldNotBeEmpty)
+					return nil
+				}).Times(1)
+				mockClientCachePort.EXPECT().DeleteByClientIDs(gomock.Any(), []int{1}).Return(nil).Times(1)
+
+				results, err := clientDomain.Client().Upsert(context.Background(), inputs)
+				So(err, ShouldBeNil)
+				So(results[0].BearerKey, ShouldNotBeEmpty)
+			})
+
+			Convey("Cache client invalidate error", func() {
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return([]model.ClientKey{clientKey}, nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteByClientIDs(gomock.Any(), []int{1}).Return(errors.New("error")).Times(1)
 
 				_, err := clientDomain.Client().Upsert(context.Background(), inputs)
 				So(err, ShouldNotBeNil)
 			})
 
 			Convey("Success", func() {
-				mockClientDatabasePort.EXPECT().Upsert(gomock.Any()).Return(nil).Times(1)
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return([]model.ClientKey{clientKey}, nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteByClientIDs(gomock.Any(), []int{1}).Return(nil).Times(1)
 
 				results, err := clientDomain.Client().Upsert(context.Background(), inputs)
 				So(err, ShouldBeNil)
@@ -96,25 +171,70 @@ func TestClient(t *testing.T) {
 
 		Convey("FindByFilter", func() {
 			Convey("Filter is empty", func() {
-				_, err := clientDomain.Client().FindByFilter(context.Background(), model.ClientFilter{})
+				_, _, err := clientDomain.Client().FindByFilter(context.Background(), model.ClientFilter{})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Invalid sort", func() {
+				_, _, err := clientDomain.Client().FindByFilter(context.Background(), model.ClientFilter{
+					IDs:  []int{1},
+					Sort: []model.Sort{{Field: "bearer_key"}},
+				})
 				So(err, ShouldNotBeNil)
 			})
 
 			Convey("Database client find by filter error", func() {
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
 
-				_, err := clientDomain.Client().FindByFilter(context.Background(), filter)
+				_, _, err := clientDomain.Client().FindByFilter(context.Background(), filter)
 				So(err, ShouldNotBeNil)
 			})
 
 			Convey("Success", func() {
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
 
-				results, err := clientDomain.Client().FindByFilter(context.Background(), filter)
+				results, page, err := clientDomain.Client().FindByFilter(context.Background(), filter)
 				So(err, ShouldBeNil)
+				So(page, ShouldBeNil)
 				So(results, ShouldNotBeEmpty)
 				So(results[0].Name, ShouldEqual, "Test Client")
 			})
+
+			Convey("Paged", func() {
+				paged := model.ClientFilter{Page: model.Pagination{Limit: 1}}
+				mockClientDatabasePort.EXPECT().CountByFilter(gomock.Any(), gomock.Any()).Return(2, nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), false).
+					DoAndReturn(func(_ context.Context, filter model.ClientFilter, _ bool) ([]model.Client, error) {
+						So(filter.Page.Limit, ShouldEqual, 2)
+						So(filter.Sort, ShouldResemble, []model.Sort{{Field: "id", Direction: model.SortAsc}})
+						return []model.Client{{ID: 1}, {ID: 2}}, nil
+					}).Times(1)
+
+				results, page, err := clientDomain.Client().FindByFilter(context.Background(), paged)
+				So(err, ShouldBeNil)
+				So(results, ShouldHaveLength, 1)
+				So(page.Total, ShouldEqual, 2)
+				So(page.Limit, ShouldEqual, 1)
+				So(page.NextCursor, ShouldNotBeEmpty)
+			})
+
+			Convey("Negative page", func() {
+				for _, page := range []model.Pagination{{Limit: -1}, {Limit: 1, Offset: -1}} {
+					_, _, err := clientDomain.Client().FindByFilter(context.Background(), model.ClientFilter{Page: page})
+					So(stacktrace.GetCode(err), ShouldEqual, model.ErrorCodeValidation)
+				}
+			})
+
+			Convey("Last page has no cursor", func() {
+				paged := model.ClientFilter{Page: model.Pagination{Limit: 2}}
+				mockClientDatabasePort.EXPECT().CountByFilter(gomock.Any(), gomock.Any()).Return(2, nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), false).Return([]model.Client{{ID: 1}, {ID: 2}}, nil).Times(1)
+
+				results, page, err := clientDomain.Client().FindByFilter(context.Background(), paged)
+				So(err, ShouldBeNil)
+				So(results, ShouldHaveLength, 2)
+				So(page.NextCursor, ShouldBeEmpty)
+			})
 		})
 
 		Convey("DeleteByFilter", func() {
@@ -123,15 +243,25 @@ func TestClient(t *testing.T) {
 				So(err, ShouldNotBeNil)
 			})
 
+			Convey("Database client find by filter error", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
+
+				err := clientDomain.Client().DeleteByFilter(context.Background(), filter)
+				So(err, ShouldNotBeNil)
+			})
+
 			Convey("Database client delete by filter error", func() {
-				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any()).Return(errors.New("error")).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any(), gomock.Any()).Return(errors.New("error")).Times(1)
 
 				err := clientDomain.Client().DeleteByFilter(context.Background(), filter)
 				So(err, ShouldNotBeNil)
 			})
 
-			Convey("Success", func() {
-				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any()).Return(nil).Times(1)
+			Convey("Success evicts the deleted clients from cache", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientDatabasePort.EXPECT().DeleteByFilter(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteByClientIDs(gomock.Any(), []int{1}).Return(nil).Times(1)
 
 				err := clientDomain.Client().DeleteByFilter(context.Background(), filter)
 				So(err, ShouldBeNil)
@@ -144,15 +274,21 @@ func TestClient(t *testing.T) {
 				So(err, ShouldNotBeNil)
 			})
 
-			Convey("Message client publish upsert error", func() {
-				mockClientMessagePort.EXPECT().PublishUpsert(gomock.Any()).Return(errors.New("error")).Times(1)
+			Convey("Outbox insert error", func() {
+				mockOutboxDatabasePort.EXPECT().Insert(gomock.Any(), gomock.Any()).Return(errors.New("error")).Times(1)
 
 				err := clientDomain.Client().PublishUpsert(context.Background(), inputs)
 				So(err, ShouldNotBeNil)
 			})
 
-			Convey("Success", func() {
-				mockClientMessagePort.EXPECT().PublishUpsert(gomock.Any()).Return(nil).Times(1)
+			Convey("Success writes a message per client to the outbox", func() {
+				mockOutboxDatabasePort.EXPECT().Insert(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, datas []model.OutboxMessageInput) error {
+						So(datas, ShouldHaveLength, 1)
+						So(datas[0].Topic, ShouldEqual, model.UpsertClientMessage)
+						So(datas[0].AggregateID, ShouldEqual, "Test Client")
+						return nil
+					}).Times(1)
 
 				err := clientDomain.Client().PublishUpsert(context.Background(), inputs)
 				So(err, ShouldBeNil)
@@ -166,56 +302,239 @@ func TestClient(t *testing.T) {
 			})
 
 			Convey("Cache client get error", func() {
-				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(model.Client{}, errors.New("error")).Times(1)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, errors.New("error")).Times(1)
 
-				_, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-key")
+				_, err := clientDomain.Client().IsExists(context.Background(), bearerKey)
 				So(err, ShouldNotBeNil)
 			})
 
-			Convey("Database client is exists error", func() {
-				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
-				mockClientDatabasePort.EXPECT().IsExists(gomock.Any()).Return(false, errors.New("error")).Times(1)
+			Convey("Database client key find by filter error", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, outbound_port.ErrCacheMiss).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
 
-				_, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-key")
+				_, err := clientDomain.Client().IsExists(context.Background(), bearerKey)
 				So(err, ShouldNotBeNil)
 			})
 
 			Convey("Database client find by filter error", func() {
-				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
-				mockClientDatabasePort.EXPECT().IsExists(gomock.Any()).Return(true, nil).Times(1)
-
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, outbound_port.ErrCacheMiss).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return([]model.ClientKey{clientKey}, nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error")).Times(1)
 
-				_, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-key")
+				_, err := clientDomain.Client().IsExists(context.Background(), bearerKey)
 				So(err, ShouldNotBeNil)
 			})
 
 			Convey("Cache client set error", func() {
-				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
-				mockClientDatabasePort.EXPECT().IsExists(gomock.Any()).Return(true, nil).Times(1)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, outbound_port.ErrCacheMiss).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return([]model.ClientKey{clientKey}, nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientRoleDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				mockClientCachePort.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("error")).Times(1)
 
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
-				mockClientCachePort.EXPECT().Set(gomock.Any()).Return(errors.New("error")).Times(1)
-
-				_, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-key")
+				_, err := clientDomain.Client().IsExists(context.Background(), bearerKey)
 				So(err, ShouldNotBeNil)
 			})
 
 			Convey("Success", func() {
-				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(model.Client{}, redis.Nil).Times(1)
-				mockClientDatabasePort.EXPECT().IsExists(gomock.Any()).Return(true, nil).Times(1)
-				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
-				mockClientCachePort.EXPECT().Set(gomock.Any()).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, outbound_port.ErrCacheMiss).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), model.ClientKeyFilter{Prefixes: []string{model.BearerKeyPrefix(bearerKey)}}).Return([]model.ClientKey{clientKey}, nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientRoleDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				mockClientCachePort.EXPECT().Set(gomock.Any(), bearerKey, gomock.Any(), int64(3)).Return(nil).Times(1)
 
-				result, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-key")
+				result, err := clientDomain.Client().IsExists(context.Background(), bearerKey)
 				So(err, ShouldBeNil)
 				So(result, ShouldBeTrue)
 			})
 
 			Convey("Cache client exists", func() {
-				mockClientCachePort.EXPECT().Get(gomock.Any()).Return(outputs[0], nil).Times(1)
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(outputs[0], nil).Times(1)
+
+				_, err := clientDomain.Client().IsExists(context.Background(), bearerKey)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("FindByBearerKey", func() {
+			Convey("Bearer key is empty", func() {
+				_, _, err := clientDomain.Client().FindByBearerKey(context.Background(), "")
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Cache client exists", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(outputs[0], nil).Times(1)
+
+				client, exists, err := clientDomain.Client().FindByBearerKey(context.Background(), bearerKey)
+				So(err, ShouldBeNil)
+				So(exists, ShouldBeTrue)
+				So(client.ID, ShouldEqual, 1)
+			})
+
+			Convey("Cached client key expired", func() {
+				expired := outputs[0]
+				expiresAt := time.Now().Add(-time.Minute)
+				expired.KeyExpiresAt = &expiresAt
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(expired, nil).Times(1)
+				mockClientCachePort.EXPECT().Delete(gomock.Any(), bearerKey).Return(nil).Times(1)
+
+				_, exists, err := clientDomain.Client().FindByBearerKey(context.Background(), bearerKey)
+				So(err, ShouldBeNil)
+				So(exists, ShouldBeFalse)
+			})
+
+			Convey("Database client exists", func() {
+				expiresAt := time.Now().Add(time.Hour)
+				clientKey.ExpiresAt = &expiresAt
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, outbound_port.ErrCacheMiss).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return([]model.ClientKey{clientKey}, nil).Times(1)
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), model.ClientFilter{IDs: []int{1}}, false).Return(outputs, nil).Times(1)
+				mockClientRoleDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+				mockClientCachePort.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				client, exists, err := clientDomain.Client().FindByBearerKey(context.Background(), bearerKey)
+				So(err, ShouldBeNil)
+				So(exists, ShouldBeTrue)
+				So(client.ID, ShouldEqual, 1)
+				So(client.KeyExpiresAt, ShouldEqual, &expiresAt)
+			})
+
+			Convey("Key with the same prefix but a different secret", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, outbound_port.ErrCacheMiss).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return([]model.ClientKey{clientKey}, nil).Times(1)
+
+				_, exists, err := clientDomain.Client().FindByBearerKey(context.Background(), model.BearerKeyPrefix(bearerKey)+"wrong-secret")
+				So(err, ShouldBeNil)
+				So(exists, ShouldBeFalse)
+			})
+
+			Convey("Database client key expired", func() {
+				expiresAt := time.Now().Add(-time.Minute)
+				clientKey.ExpiresAt = &expiresAt
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, outbound_port.ErrCacheMiss).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return([]model.ClientKey{clientKey}, nil).Times(1)
+
+				_, exists, err := clientDomain.Client().FindByBearerKey(context.Background(), bearerKey)
+				So(err, ShouldBeNil)
+				So(exists, ShouldBeFalse)
+			})
+
+			Convey("Client does not exist", func() {
+				mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, outbound_port.ErrCacheMiss).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+
+				_, exists, err := clientDomain.Client().FindByBearerKey(context.Background(), bearerKey)
+				So(err, ShouldBeNil)
+				So(exists, ShouldBeFalse)
+			})
+
+			Convey("With negative caching and coalesced lookups", func() {
+				clientDomain := domain.NewDomain(mockDatabasePort, mockMessagePort, mockCachePort, mockWorkflowPort,
+					domain.WithClientOptions(client.Options{NegativeTTL: time.Minute, CoalesceLookups: true}))
+
+				Convey("Unknown key is cached as unknown", func() {
+					mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, outbound_port.ErrCacheMiss).Times(1)
+					mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+					mockClientCachePort.EXPECT().SetUnknown(gomock.Any(), bearerKey, time.Minute).Return(nil).Times(1)
+
+					_, exists, err := clientDomain.Client().FindByBearerKey(context.Background(), bearerKey)
+					So(err, ShouldBeNil)
+					So(exists, ShouldBeFalse)
+				})
+
+				Convey("Cache set unknown error", func() {
+					mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, outbound_port.ErrCacheMiss).Times(1)
+					mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+					mockClientCachePort.EXPECT().SetUnknown(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("error")).Times(1)
+
+					_, _, err := clientDomain.Client().FindByBearerKey(context.Background(), bearerKey)
+					So(err, ShouldNotBeNil)
+				})
+
+				Convey("Cached unknown key skips the database", func() {
+					mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).Return(model.Client{}, outbound_port.ErrClientUnknown).Times(1)
+
+					_, exists, err := clientDomain.Client().FindByBearerKey(context.Background(), bearerKey)
+					So(err, ShouldBeNil)
+					So(exists, ShouldBeFalse)
+				})
+
+				Convey("Concurrent misses hit the database once", func() {
+					const lookups = 5
+					var misses sync.WaitGroup
+					misses.Add(lookups)
+					mockClientCachePort.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, string) (model.Client, error) {
+						misses.Done()
+						return model.Client{}, outbound_port.ErrCacheMiss
+					}).Times(lookups)
+					mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, model.ClientKeyFilter) ([]model.ClientKey, error) {
+						misses.Wait()
+						time.Sleep(20 * time.Millisecond)
+						return []model.ClientKey{clientKey}, nil
+					}).Times(1)
+					mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+					mockClientRoleDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+					mockClientCachePort.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+					var wg sync.WaitGroup
+					found := make([]bool, lookups)
+					errs := make([]error, lookups)
+					for i := 0; i < lookups; i++ {
+						wg.Add(1)
+						go func(i int) {
+							defer wg.Done()
+							_, found[i], errs[i] = clientDomain.Client().FindByBearerKey(context.Background(), bearerKey)
+						}(i)
+					}
+					wg.Wait()
+
+					for i := 0; i < lookups; i++ {
+						So(errs[i], ShouldBeNil)
+						So(found[i], ShouldBeTrue)
+					}
+				})
+			})
+		})
+
+		Convey("UpsertKey", func() {
+			Convey("Client id is empty", func() {
+				_, err := clientDomain.Client().UpsertKey(context.Background(), model.ClientKeyInput{})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Client does not exist", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+
+				_, err := clientDomain.Client().UpsertKey(context.Background(), model.ClientKeyInput{ClientID: 1})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success returns the key once", func() {
+				mockClientDatabasePort.EXPECT().FindByFilter(gomock.Any(), gomock.Any(), gomock.Any()).Return(outputs, nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), model.ClientKeyFilter{ClientIDs: []int{1}, Labels: []string{"rotation"}}).Return([]model.ClientKey{clientKey}, nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteByClientIDs(gomock.Any(), []int{1}).Return(nil).Times(1)
+
+				result, err := clientDomain.Client().UpsertKey(context.Background(), model.ClientKeyInput{ClientID: 1, Label: "rotation"})
+				So(err, ShouldBeNil)
+				So(result.BearerKey, ShouldNotBeEmpty)
+				So(model.BearerKeyPrefix(result.BearerKey), ShouldNotBeEmpty)
+			})
+		})
+
+		Convey("DeleteKeysByFilter", func() {
+			Convey("Filter is empty", func() {
+				err := clientDomain.Client().DeleteKeysByFilter(context.Background(), model.ClientKeyFilter{})
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Success evicts the clients of the deleted keys from cache", func() {
+				keyFilter := model.ClientKeyFilter{ClientIDs: []int{1}, Labels: []string{model.DefaultClientKeyLabel}}
+				mockClientKeyDatabasePort.EXPECT().FindByFilter(gomock.Any(), keyFilter).Return([]model.ClientKey{clientKey, clientKey}, nil).Times(1)
+				mockClientKeyDatabasePort.EXPECT().DeleteByFilter(gomock.Any(), keyFilter).Return(nil).Times(1)
+				mockClientCachePort.EXPECT().DeleteByClientIDs(gomock.Any(), []int{1}).Return(nil).Times(1)
 
-				_, err := clientDomain.Client().IsExists(context.Background(), "test-bearer-key")
+				err := clientDomain.Client().DeleteKeysByFilter(context.Background(), keyFilter)
 				So(err, ShouldBeNil)
 			})
 		})
@@ -0,0 +1,175 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/deadline"
+)
+
+type HealthDomain interface {
+	// Ready checks every configured dependency, the result is reused for Options.CacheTTL.
+	// Ports without a driver report model.ErrNotConfigured and are left out. The inbound
+	// components are reported as they were last marked.
+	Ready(ctx context.Context) model.Health
+	// Started marks the inbound component name as serving
+	Started(name string)
+	// Stopped marks the inbound component name as down with err, or leaves it out when
+	// it ended without error
+	Stopped(name string, err error)
+}
+
+// Options tunes the readiness checks
+type Options struct {
+	// CacheTTL reuses the last result for this long, so frequent probes do not load the dependencies
+	CacheTTL time.Duration
+	// Timeout bounds the checks, 0 leaves them unbounded
+	Timeout time.Duration
+}
+
+// pinger is the part of every outbound registry the checks use
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type healthDomain struct {
+	components map[string]pinger
+	options    Options
+
+	mu   sync.Mutex
+	last model.Health
+
+	inboundsMu sync.Mutex
+	inbounds   map[string]model.ComponentHealth
+}
+
+// NewHealthDomain checks the given ports, nil ports are not configured and skipped
+func NewHealthDomain(
+	databasePort outbound_port.DatabasePort,
+	messagePort outbound_port.MessagePort,
+	cachePort outbound_port.CachePort,
+	workflowPort outbound_port.WorkflowPort,
+	options Options,
+) HealthDomain {
+	components := map[string]pinger{}
+	if databasePort != nil {
+		components["database"] = databasePort
+	}
+	if messagePort != nil {
+		components["message"] = messagePort
+	}
+	if cachePort != nil {
+		components["cache"] = cachePort
+	}
+	if workflowPort != nil {
+		components["workflow"] = workflowPort
+	}
+
+	return &healthDomain{
+		components: components,
+		options:    options,
+		inbounds:   map[string]model.ComponentHealth{},
+	}
+}
+
+func (s *healthDomain) Ready(ctx context.Context) model.Health {
+	health := s.dependencies(ctx)
+
+	s.inboundsMu.Lock()
+	defer s.inboundsMu.Unlock()
+
+	if len(s.inbounds) == 0 {
+		return health
+	}
+	health.Inbounds = make(map[string]model.ComponentHealth, len(s.inbounds))
+	for name, inbound := range s.inbounds {
+		health.Inbounds[name] = inbound
+		if inbound.Status != model.HealthStatusUp {
+			health.Status = model.HealthStatusDown
+		}
+	}
+	return health
+}
+
+func (s *healthDomain) Started(name string) {
+	s.inboundsMu.Lock()
+	defer s.inboundsMu.Unlock()
+
+	s.inbounds[name] = model.ComponentHealth{Status: model.HealthStatusUp}
+}
+
+func (s *healthDomain) Stopped(name string, err error) {
+	s.inboundsMu.Lock()
+	defer s.inboundsMu.Unlock()
+
+	if err == nil {
+		delete(s.inbounds, name)
+		return
+	}
+	s.inbounds[name] = model.ComponentHealth{Status: model.HealthStatusDown, Error: err.Error()}
+}
+
+// dependencies checks the outbound ports, or returns the last result within the cache ttl
+func (s *healthDomain) dependencies(ctx context.Context) model.Health {
+	// concurrent probes wait for one check instead of starting their own
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.last.CheckedAt.IsZero() && time.Since(s.last.CheckedAt) < s.options.CacheTTL {
+		return s.last
+	}
+
+	ctx, cancel := deadline.WithTimeout(ctx, s.options.Timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	health := model.Health{
+		Status:     model.HealthStatusUp,
+		Components: make(map[string]model.ComponentHealth, len(s.components)),
+	}
+	for name, component := range s.components {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, configured := check(ctx, component)
+			if !configured {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			health.Components[name] = result
+			if result.Status != model.HealthStatusUp {
+				health.Status = model.HealthStatusDown
+			}
+		}()
+	}
+	wg.Wait()
+
+	health.CheckedAt = time.Now()
+	s.last = health
+	return health
+}
+
+func check(ctx context.Context, component pinger) (model.ComponentHealth, bool) {
+	start := time.Now()
+	err := component.Ping(ctx)
+	if errors.Is(err, model.ErrNotConfigured) {
+		return model.ComponentHealth{}, false
+	}
+
+	result := model.ComponentHealth{
+		Status:    model.HealthStatusUp,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = model.HealthStatusDown
+		result.Error = err.Error()
+	}
+	return result, true
+}
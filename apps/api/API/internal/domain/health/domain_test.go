@@ -0,0 +1,98 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	none_outbound_adapter "prabogo/internal/adapter/outbound/none"
+	"prabogo/internal/domain/health"
+	"prabogo/internal/model"
+	mock_outbound_port "prabogo/tests/mocks/port"
+)
+
+func TestHealth(t *testing.T) {
+	Convey("Test Health", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		ctx := context.Background()
+
+		Convey("Up when every configured component is up", func() {
+			mockDatabasePort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+			mockCachePort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+			domain := health.NewHealthDomain(mockDatabasePort, nil, mockCachePort, nil, health.Options{})
+
+			result := domain.Ready(ctx)
+			So(result.IsUp(), ShouldBeTrue)
+			So(result.Components, ShouldHaveLength, 2)
+			So(result.Components["database"].Status, ShouldEqual, model.HealthStatusUp)
+		})
+
+		Convey("Down when a component fails", func() {
+			mockDatabasePort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+			mockCachePort.EXPECT().Ping(gomock.Any()).Return(errors.New("connection refused")).Times(1)
+			domain := health.NewHealthDomain(mockDatabasePort, nil, mockCachePort, nil, health.Options{})
+
+			result := domain.Ready(ctx)
+			So(result.IsUp(), ShouldBeFalse)
+			So(result.Components["database"].Status, ShouldEqual, model.HealthStatusUp)
+			So(result.Components["cache"].Status, ShouldEqual, model.HealthStatusDown)
+			So(result.Components["cache"].Error, ShouldEqual, "connection refused")
+		})
+
+		Convey("Leaves out ports without a driver", func() {
+			mockDatabasePort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+			cachePort := none_outbound_adapter.NewCacheAdapter(none_outbound_adapter.NotConfigured("cache"))
+			domain := health.NewHealthDomain(mockDatabasePort, nil, cachePort, nil, health.Options{})
+
+			result := domain.Ready(ctx)
+			So(result.IsUp(), ShouldBeTrue)
+			So(result.Components, ShouldHaveLength, 1)
+		})
+
+		Convey("Checks are bounded by the health deadline", func() {
+			mockDatabasePort.EXPECT().Ping(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}).Times(1)
+			domain := health.NewHealthDomain(mockDatabasePort, nil, nil, nil, health.Options{Timeout: 10 * time.Millisecond})
+
+			result := domain.Ready(ctx)
+			So(result.IsUp(), ShouldBeFalse)
+		})
+
+		Convey("Reuses the result within the cache TTL", func() {
+			mockDatabasePort.EXPECT().Ping(gomock.Any()).Return(nil).Times(1)
+			domain := health.NewHealthDomain(mockDatabasePort, nil, nil, nil, health.Options{CacheTTL: time.Minute})
+
+			first := domain.Ready(ctx)
+			second := domain.Ready(ctx)
+			So(second.CheckedAt, ShouldEqual, first.CheckedAt)
+		})
+
+		Convey("Reports the inbound components as marked", func() {
+			mockDatabasePort.EXPECT().Ping(gomock.Any()).Return(nil).AnyTimes()
+			domain := health.NewHealthDomain(mockDatabasePort, nil, nil, nil, health.Options{CacheTTL: time.Minute})
+			domain.Started("http")
+			domain.Started("message")
+
+			result := domain.Ready(ctx)
+			So(result.IsUp(), ShouldBeTrue)
+			So(result.Inbounds, ShouldHaveLength, 2)
+
+			domain.Stopped("message", errors.New("channel closed"))
+			domain.Stopped("http", nil)
+			result = domain.Ready(ctx)
+			So(result.IsUp(), ShouldBeFalse)
+			So(result.Inbounds, ShouldHaveLength, 1)
+			So(result.Inbounds["message"].Error, ShouldEqual, "channel closed")
+		})
+	})
+}
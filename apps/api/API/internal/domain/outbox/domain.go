@@ -0,0 +1,114 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/palantir/stacktrace"
+
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+type OutboxDomain interface {
+	// Relay publishes one batch of pending messages and returns how many it found. A
+	// message that fails to publish is retried after a growing delay, the later messages
+	// of its aggregate wait for it.
+	Relay(ctx context.Context) (int, error)
+	// Cleanup deletes the messages published longer than Options.Retention ago
+	Cleanup(ctx context.Context) error
+}
+
+// Options tunes the relay
+type Options struct {
+	// BatchSize is the messages published per transaction
+	BatchSize int
+	// RetryMin is the delay after the first failure, it doubles on every failure up to RetryMax
+	RetryMin time.Duration
+	RetryMax time.Duration
+	// Retention keeps published messages for this long, 0 keeps them
+	Retention time.Duration
+}
+
+type outboxDomain struct {
+	databasePort outbound_port.DatabasePort
+	messagePort  outbound_port.MessagePort
+	options      Options
+}
+
+func NewOutboxDomain(
+	databasePort outbound_port.DatabasePort,
+	messagePort outbound_port.MessagePort,
+	options Options,
+) OutboxDomain {
+	return &outboxDomain{
+		databasePort: databasePort,
+		messagePort:  messagePort,
+		options:      options,
+	}
+}
+
+// Relay holds the row locks of the batch until it is marked, so concurrent relays
+// publish different aggregates
+func (s *outboxDomain) Relay(ctx context.Context) (int, error) {
+	out, err := s.databasePort.DoInTransaction(ctx, func(tx outbound_port.DatabasePort) (interface{}, error) {
+		now := time.Now().UTC()
+		outboxPort := tx.Outbox()
+		messages, err := outboxPort.FindPending(ctx, now, max(s.options.BatchSize, 1))
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "find pending outbox message error")
+		}
+
+		var published []int
+		messageOutboxPort := s.messagePort.Outbox()
+		for _, message := range messages {
+			err := messageOutboxPort.Publish(ctx, message)
+			if err == nil {
+				published = append(published, message.ID)
+				continue
+			}
+
+			err = outboxPort.MarkFailed(ctx, message.ID, err.Error(), now.Add(s.retryDelay(message.Attempts)))
+			if err != nil {
+				return nil, stacktrace.Propagate(err, "mark outbox message failed error")
+			}
+		}
+
+		err = outboxPort.MarkPublished(ctx, published, now)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "mark outbox message published error")
+		}
+
+		return len(messages), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return out.(int), nil
+}
+
+func (s *outboxDomain) Cleanup(ctx context.Context) error {
+	if s.options.Retention <= 0 {
+		return nil
+	}
+
+	err := s.databasePort.Outbox().DeletePublished(ctx, time.Now().UTC().Add(-s.options.Retention))
+	if err != nil {
+		return stacktrace.Propagate(err, "delete published outbox message error")
+	}
+
+	return nil
+}
+
+// retryDelay is the wait after a message failed attempts times before, doubling from
+// RetryMin up to RetryMax
+func (s *outboxDomain) retryDelay(attempts int) time.Duration {
+	delay := s.options.RetryMin
+	for i := 0; i < attempts && delay < s.options.RetryMax; i++ {
+		delay *= 2
+	}
+	if s.options.RetryMax > 0 {
+		delay = min(delay, s.options.RetryMax)
+	}
+	return delay
+}
@@ -0,0 +1,79 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain/outbox"
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+	mock_outbound_port "prabogo/tests/mocks/port"
+)
+
+func TestOutbox(t *testing.T) {
+	Convey("Test Outbox", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDatabasePort := mock_outbound_port.NewMockDatabasePort(mockCtrl)
+		mockMessagePort := mock_outbound_port.NewMockMessagePort(mockCtrl)
+		mockOutboxDatabasePort := mock_outbound_port.NewMockOutboxDatabasePort(mockCtrl)
+		mockOutboxMessagePort := mock_outbound_port.NewMockOutboxMessagePort(mockCtrl)
+
+		mockDatabasePort.EXPECT().Outbox().Return(mockOutboxDatabasePort).AnyTimes()
+		mockMessagePort.EXPECT().Outbox().Return(mockOutboxMessagePort).AnyTimes()
+		mockDatabasePort.EXPECT().DoInTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, txFunc outbound_port.InTransaction) (interface{}, error) {
+				return txFunc(mockDatabasePort)
+			}).AnyTimes()
+
+		options := outbox.Options{BatchSize: 10, RetryMin: time.Second, RetryMax: 4 * time.Second, Retention: time.Hour}
+		domain := outbox.NewOutboxDomain(mockDatabasePort, mockMessagePort, options)
+		ctx := context.Background()
+
+		messages := []model.OutboxMessage{
+			{ID: 1, OutboxMessageInput: model.OutboxMessageInput{Topic: model.UpsertClientMessage, AggregateID: "a"}},
+			{ID: 2, OutboxMessageInput: model.OutboxMessageInput{Topic: model.UpsertClientMessage, AggregateID: "b"}, Attempts: 3},
+		}
+
+		Convey("Relay", func() {
+			Convey("Marks the published messages and delays the failed ones", func() {
+				mockOutboxDatabasePort.EXPECT().FindPending(gomock.Any(), gomock.Any(), 10).Return(messages, nil).Times(1)
+				mockOutboxMessagePort.EXPECT().Publish(gomock.Any(), messages[0]).Return(nil).Times(1)
+				mockOutboxMessagePort.EXPECT().Publish(gomock.Any(), messages[1]).Return(errors.New("channel closed")).Times(1)
+				mockOutboxDatabasePort.EXPECT().MarkFailed(gomock.Any(), 2, "channel closed", gomock.Any()).DoAndReturn(
+					func(ctx context.Context, id int, reason string, availableAt time.Time) error {
+						So(time.Until(availableAt), ShouldBeBetween, 3*time.Second, 4*time.Second)
+						return nil
+					}).Times(1)
+				mockOutboxDatabasePort.EXPECT().MarkPublished(gomock.Any(), []int{1}, gomock.Any()).Return(nil).Times(1)
+
+				found, err := domain.Relay(ctx)
+				So(err, ShouldBeNil)
+				So(found, ShouldEqual, 2)
+			})
+
+			Convey("Find pending error", func() {
+				mockOutboxDatabasePort.EXPECT().FindPending(gomock.Any(), gomock.Any(), 10).Return(nil, errors.New("error")).Times(1)
+
+				_, err := domain.Relay(ctx)
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("Cleanup deletes the messages published before the retention", func() {
+			mockOutboxDatabasePort.EXPECT().DeletePublished(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, before time.Time) error {
+					So(time.Since(before), ShouldBeBetween, time.Hour-time.Second, time.Hour+time.Second)
+					return nil
+				}).Times(1)
+
+			So(domain.Cleanup(ctx), ShouldBeNil)
+		})
+	})
+}
@@ -0,0 +1,39 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/ratelimit"
+)
+
+type RateLimitDomain interface {
+	// Allow records a hit for key and reports whether it is within limit. The hits are
+	// counted in the cache, in memory while the cache fails.
+	Allow(ctx context.Context, key string, limit ratelimit.Limit) ratelimit.Result
+}
+
+type rateLimitDomain struct {
+	limiter *ratelimit.Limiter
+}
+
+func NewRateLimitDomain(cachePort outbound_port.CachePort) RateLimitDomain {
+	return &rateLimitDomain{
+		limiter: ratelimit.NewLimiter(&cacheStore{cachePort: cachePort}),
+	}
+}
+
+func (s *rateLimitDomain) Allow(ctx context.Context, key string, limit ratelimit.Limit) ratelimit.Result {
+	return s.limiter.Allow(ctx, key, limit)
+}
+
+// cacheStore asks the cache for its store on every hit, so a cache that connects on
+// first use counts the hits from then on
+type cacheStore struct {
+	cachePort outbound_port.CachePort
+}
+
+func (s *cacheStore) Hit(ctx context.Context, key string, limit ratelimit.Limit) (bool, int, time.Time, error) {
+	return s.cachePort.RateLimit().Hit(ctx, key, limit)
+}
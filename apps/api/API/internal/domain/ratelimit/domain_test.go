@@ -0,0 +1,34 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/domain"
+	mock_outbound_port "prabogo/tests/mocks/port"
+	"prabogo/utils/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	Convey("Test RateLimit", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockCachePort := mock_outbound_port.NewMockCachePort(mockCtrl)
+		dom := domain.NewDomain(nil, nil, mockCachePort, nil)
+		limit := ratelimit.Limit{Requests: 1, Window: time.Minute}
+		ctx := context.Background()
+
+		Convey("Counts the hits in the store the cache has at each hit", func() {
+			store := ratelimit.NewMemoryStore()
+			mockCachePort.EXPECT().RateLimit().Return(store).Times(2)
+
+			So(dom.RateLimit().Allow(ctx, "client:1", limit).Allowed, ShouldBeTrue)
+			So(dom.RateLimit().Allow(ctx, "client:1", limit).Allowed, ShouldBeFalse)
+		})
+	})
+}
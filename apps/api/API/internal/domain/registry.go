@@ -2,18 +2,54 @@ package domain
 
 import (
 	"prabogo/internal/domain/client"
+	"prabogo/internal/domain/health"
+	"prabogo/internal/domain/outbox"
+	"prabogo/internal/domain/ratelimit"
 	outbound_port "prabogo/internal/port/outbound"
 )
 
 type Domain interface {
 	Client() client.ClientDomain
+	Health() health.HealthDomain
+	Outbox() outbox.OutboxDomain
+	RateLimit() ratelimit.RateLimitDomain
 }
 
 type domain struct {
-	databasePort outbound_port.DatabasePort
-	messagePort  outbound_port.MessagePort
-	cachePort    outbound_port.CachePort
-	workflowPort outbound_port.WorkflowPort
+	databasePort    outbound_port.DatabasePort
+	messagePort     outbound_port.MessagePort
+	cachePort       outbound_port.CachePort
+	workflowPort    outbound_port.WorkflowPort
+	clientOptions   client.Options
+	clientDomain    client.ClientDomain
+	healthOptions   health.Options
+	healthDomain    health.HealthDomain
+	outboxOptions   outbox.Options
+	rateLimitDomain ratelimit.RateLimitDomain
+}
+
+// Option configures the domain
+type Option func(*domain)
+
+// WithClientOptions sets the bearer key lookup options of the client domain
+func WithClientOptions(options client.Options) Option {
+	return func(d *domain) {
+		d.clientOptions = options
+	}
+}
+
+// WithHealthOptions sets the readiness check options of the health domain
+func WithHealthOptions(options health.Options) Option {
+	return func(d *domain) {
+		d.healthOptions = options
+	}
+}
+
+// WithOutboxOptions sets the relay options of the outbox domain
+func WithOutboxOptions(options outbox.Options) Option {
+	return func(d *domain) {
+		d.outboxOptions = options
+	}
 }
 
 func NewDomain(
@@ -21,15 +57,40 @@ func NewDomain(
 	messagePort outbound_port.MessagePort,
 	cachePort outbound_port.CachePort,
 	workflowPort outbound_port.WorkflowPort,
+	options ...Option,
 ) Domain {
-	return &domain{
+	d := &domain{
 		databasePort: databasePort,
 		messagePort:  messagePort,
 		cachePort:    cachePort,
 		workflowPort: workflowPort,
 	}
+	for _, option := range options {
+		option(d)
+	}
+
+	// The client domain is shared so concurrent lookups can be coalesced
+	d.clientDomain = client.NewClientDomain(d.databasePort, d.messagePort, d.cachePort, d.workflowPort, d.clientOptions)
+	// The rate limit domain is shared so the memory fallback counts every request
+	d.rateLimitDomain = ratelimit.NewRateLimitDomain(d.cachePort)
+	// The health domain is shared so its cached result serves every probe
+	d.healthDomain = health.NewHealthDomain(d.databasePort, d.messagePort, d.cachePort, d.workflowPort, d.healthOptions)
+
+	return d
 }
 
 func (d *domain) Client() client.ClientDomain {
-	return client.NewClientDomain(d.databasePort, d.messagePort, d.cachePort, d.workflowPort)
+	return d.clientDomain
+}
+
+func (d *domain) Health() health.HealthDomain {
+	return d.healthDomain
+}
+
+func (d *domain) RateLimit() ratelimit.RateLimitDomain {
+	return d.rateLimitDomain
+}
+
+func (d *domain) Outbox() outbox.OutboxDomain {
+	return outbox.NewOutboxDomain(d.databasePort, d.messagePort, d.outboxOptions)
 }
@@ -0,0 +1,284 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"prabogo/internal/domain"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/utils/config"
+	"prabogo/utils/lifecycle"
+)
+
+// Kind is the port a driver implements, it names the variable that selects the driver
+type Kind string
+
+const (
+	OutboundDatabase Kind = "OUTBOUND_DATABASE_DRIVER"
+	OutboundMessage  Kind = "OUTBOUND_MESSAGE_DRIVER"
+	OutboundCache    Kind = "OUTBOUND_CACHE_DRIVER"
+	OutboundWorkflow Kind = "OUTBOUND_WORKFLOW_DRIVER"
+	InboundHttp      Kind = "INBOUND_HTTP_DRIVER"
+	InboundMessage   Kind = "INBOUND_MESSAGE_DRIVER"
+	InboundWorkflow  Kind = "INBOUND_WORKFLOW_DRIVER"
+)
+
+// None is the outbound driver of ports left unconfigured
+const None = "none"
+
+// Outbound factories connect a port within ctx, which is the context of the call that
+// first uses the port. What outlives the connection must not stop with ctx. close
+// releases what they opened and may be nil.
+type (
+	DatabaseFactory func(ctx context.Context) (port outbound_port.DatabasePort, close func() error, err error)
+	MessageFactory  func(ctx context.Context) (port outbound_port.MessagePort, close func() error, err error)
+	CacheFactory    func(ctx context.Context) (port outbound_port.CachePort, close func() error, err error)
+	WorkflowFactory func(ctx context.Context) (port outbound_port.WorkflowPort, close func() error, err error)
+)
+
+// InboundFactory serves the domain until ctx is done and returns once its work drained.
+// What it opened is closed by a hook on manager, after every inbound returned, so the
+// inbounds of a combined run mode can share connections. args are the command line
+// arguments.
+type InboundFactory func(ctx context.Context, domain domain.Domain, manager *lifecycle.Manager, args []string) error
+
+// Driver is a named implementation of a port, declared with New
+type Driver[F any] struct {
+	Name        string
+	Description string
+
+	kind     Kind
+	settings func() any
+	bind     func(settings any) F
+}
+
+// New declares a driver reading its settings into S, a struct tagged for utils/config.
+// bind returns the factory of the driver for the settings Load read.
+func New[S, F any](name, description string, bind func(settings S) F) Driver[F] {
+	return Driver[F]{
+		Name:        name,
+		Description: description,
+		settings: func() any {
+			return new(S)
+		},
+		bind: func(settings any) F {
+			return bind(*settings.(*S))
+		},
+	}
+}
+
+// Settings returns the variables the driver reads
+func (d Driver[F]) Settings() []config.Variable {
+	// register checked the settings are a struct
+	variables, _ := config.Variables(d.settings())
+	return variables
+}
+
+// Load reads the settings of the driver from the environment and returns its factory for
+// them, with the settings for printing. The error lists every invalid or missing setting.
+func (d Driver[F]) Load() (F, any, error) {
+	settings := d.settings()
+	err := config.Load(settings)
+	if err == nil {
+		return d.bind(settings), settings, nil
+	}
+
+	causes := split(err)
+	errs := make([]error, len(causes))
+	for i, cause := range causes {
+		errs[i] = fmt.Errorf("%s=%s: %w", d.kind, d.Name, cause)
+	}
+	var zero F
+	return zero, settings, errors.Join(errs...)
+}
+
+// split returns the errors joined in err, at any depth
+func split(err error) []error {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, e := range joined.Unwrap() {
+		errs = append(errs, split(e)...)
+	}
+	return errs
+}
+
+// PrintSettings writes settings returned by Load, secrets are redacted
+func PrintSettings(w io.Writer, settings any) error {
+	return config.Print(w, settings)
+}
+
+// Info describes a registered driver
+type Info struct {
+	Kind        Kind
+	Name        string
+	Description string
+	Settings    []config.Variable
+}
+
+type registry[F any] struct {
+	mu      sync.RWMutex
+	drivers map[string]Driver[F]
+}
+
+func (r *registry[F]) register(kind Kind, d Driver[F]) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if d.Name == "" {
+		panic(fmt.Sprintf("driver: %s driver without a name", kind))
+	}
+	if d.settings == nil || d.bind == nil {
+		panic(fmt.Sprintf("driver: %s driver %q not declared with New", kind, d.Name))
+	}
+	if _, err := config.Variables(d.settings()); err != nil {
+		panic(fmt.Sprintf("driver: %s driver %q: %v", kind, d.Name, err))
+	}
+	if r.drivers == nil {
+		r.drivers = map[string]Driver[F]{}
+	}
+	if _, ok := r.drivers[d.Name]; ok {
+		panic(fmt.Sprintf("driver: %s driver %q registered twice", kind, d.Name))
+	}
+	d.kind = kind
+	r.drivers[d.Name] = d
+}
+
+func (r *registry[F]) get(name string) (Driver[F], bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.drivers[name]
+	return d, ok
+}
+
+func (r *registry[F]) infos(kind Kind) []Info {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	infos := make([]Info, 0, len(r.drivers))
+	for _, d := range r.drivers {
+		infos = append(infos, Info{Kind: kind, Name: d.Name, Description: d.Description, Settings: d.Settings()})
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+	return infos
+}
+
+var (
+	databases = &registry[DatabaseFactory]{}
+	messages  = &registry[MessageFactory]{}
+	caches    = &registry[CacheFactory]{}
+	workflows = &registry[WorkflowFactory]{}
+	inbounds  = map[Kind]*registry[InboundFactory]{
+		InboundHttp:     {},
+		InboundMessage:  {},
+		InboundWorkflow: {},
+	}
+)
+
+// RegisterDatabase makes a database driver available by name. Adapter packages call it
+// from init, registering a name twice panics.
+func RegisterDatabase(d Driver[DatabaseFactory]) {
+	databases.register(OutboundDatabase, d)
+}
+
+func RegisterMessage(d Driver[MessageFactory]) {
+	messages.register(OutboundMessage, d)
+}
+
+func RegisterCache(d Driver[CacheFactory]) {
+	caches.register(OutboundCache, d)
+}
+
+func RegisterWorkflow(d Driver[WorkflowFactory]) {
+	workflows.register(OutboundWorkflow, d)
+}
+
+// RegisterInbound makes an inbound driver of kind available by name
+func RegisterInbound(kind Kind, d Driver[InboundFactory]) {
+	r, ok := inbounds[kind]
+	if !ok {
+		panic(fmt.Sprintf("driver: %s is not an inbound kind", kind))
+	}
+	r.register(kind, d)
+}
+
+func Database(name string) (Driver[DatabaseFactory], bool) {
+	return databases.get(name)
+}
+
+func Message(name string) (Driver[MessageFactory], bool) {
+	return messages.get(name)
+}
+
+func Cache(name string) (Driver[CacheFactory], bool) {
+	return caches.get(name)
+}
+
+func Workflow(name string) (Driver[WorkflowFactory], bool) {
+	return workflows.get(name)
+}
+
+func Inbound(kind Kind, name string) (Driver[InboundFactory], bool) {
+	r, ok := inbounds[kind]
+	if !ok {
+		return Driver[InboundFactory]{}, false
+	}
+	return r.get(name)
+}
+
+// List returns the registered drivers by kind and name
+func List() []Info {
+	var infos []Info
+	infos = append(infos, databases.infos(OutboundDatabase)...)
+	infos = append(infos, messages.infos(OutboundMessage)...)
+	infos = append(infos, caches.infos(OutboundCache)...)
+	infos = append(infos, workflows.infos(OutboundWorkflow)...)
+	for _, kind := range []Kind{InboundHttp, InboundMessage, InboundWorkflow} {
+		infos = append(infos, inbounds[kind].infos(kind)...)
+	}
+	return infos
+}
+
+// Print writes the registered drivers and the settings they read to w
+func Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	var kind Kind
+	for _, info := range List() {
+		if info.Kind != kind {
+			if kind != "" {
+				fmt.Fprintln(tw)
+			}
+			kind = info.Kind
+			fmt.Fprintf(tw, "%s\n", kind)
+		}
+
+		fmt.Fprintf(tw, "  %s\t%s\n", info.Name, info.Description)
+		for _, setting := range info.Settings {
+			var notes []string
+			if setting.Default != "" {
+				notes = append(notes, "default "+setting.Default)
+			}
+			if setting.Secret {
+				notes = append(notes, "secret")
+			}
+			description := setting.Description
+			if len(notes) > 0 {
+				description += " (" + strings.Join(notes, ", ") + ")"
+			}
+			fmt.Fprintf(tw, "    %s\t%s\n", setting.Name, description)
+		}
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,88 @@
+package driver_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/driver"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+type fakeSettings struct {
+	Host     string `env:"FAKE_HOST" default:"localhost" description:"server host"`
+	User     string `env:"FAKE_USER" validate:"required" description:"user name"`
+	Password string `env:"FAKE_PASSWORD" secret:"true" description:"user password"`
+}
+
+func TestRegistry(t *testing.T) {
+	errFake := errors.New("fake")
+	var loaded fakeSettings
+	fake := driver.New("fake", "fake database", func(settings fakeSettings) driver.DatabaseFactory {
+		return func(context.Context) (outbound_port.DatabasePort, func() error, error) {
+			loaded = settings
+			return nil, nil, errFake
+		}
+	})
+	driver.RegisterDatabase(fake)
+
+	Convey("Test Driver Registry", t, func() {
+
+		Convey("Resolves a registered driver by name", func() {
+			d, ok := driver.Database("fake")
+			So(ok, ShouldBeTrue)
+			So(d.Name, ShouldEqual, "fake")
+
+			_, ok = driver.Database("missing")
+			So(ok, ShouldBeFalse)
+			_, ok = driver.Inbound(driver.OutboundDatabase, "fake")
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("Loads the settings of a driver", func() {
+			os.Setenv("FAKE_USER", "prabogo")
+			defer os.Unsetenv("FAKE_USER")
+			d, _ := driver.Database("fake")
+
+			connect, settings, err := d.Load()
+			So(err, ShouldBeNil)
+			So(settings, ShouldResemble, &fakeSettings{Host: "localhost", User: "prabogo"})
+
+			_, _, err = connect(context.Background())
+			So(err, ShouldEqual, errFake)
+			So(loaded.User, ShouldEqual, "prabogo")
+		})
+
+		Convey("Reports the settings missing for a driver", func() {
+			d, _ := driver.Database("fake")
+
+			connect, _, err := d.Load()
+			So(connect, ShouldBeNil)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "OUTBOUND_DATABASE_DRIVER=fake: FAKE_USER is required")
+		})
+
+		Convey("Panics on a name registered twice or a driver not declared with New", func() {
+			So(func() { driver.RegisterDatabase(fake) }, ShouldPanic)
+			So(func() {
+				driver.RegisterInbound(driver.OutboundCache, driver.Driver[driver.InboundFactory]{Name: "fake"})
+			}, ShouldPanic)
+			So(func() {
+				driver.RegisterCache(driver.Driver[driver.CacheFactory]{Name: "bare"})
+			}, ShouldPanic)
+		})
+
+		Convey("Prints the drivers and flags secrets", func() {
+			var out bytes.Buffer
+			So(driver.Print(&out), ShouldBeNil)
+			So(out.String(), ShouldContainSubstring, "OUTBOUND_DATABASE_DRIVER")
+			So(out.String(), ShouldContainSubstring, "fake database")
+			So(out.String(), ShouldContainSubstring, "server host (default localhost)")
+			So(out.String(), ShouldContainSubstring, "user password (secret)")
+		})
+	})
+}
@@ -0,0 +1,21 @@
+package internal
+
+// The built-in drivers register themselves when imported. Downstream projects add a
+// driver by importing its package the same way, from here or from their own main.
+import (
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+
+	_ "prabogo/internal/adapter/inbound/fiber"
+	_ "prabogo/internal/adapter/inbound/rabbitmq"
+	_ "prabogo/internal/adapter/inbound/temporal"
+	_ "prabogo/internal/adapter/outbound/memory"
+	_ "prabogo/internal/adapter/outbound/none"
+	_ "prabogo/internal/adapter/outbound/postgres"
+	_ "prabogo/internal/adapter/outbound/rabbitmq"
+	_ "prabogo/internal/adapter/outbound/redis"
+	_ "prabogo/internal/adapter/outbound/sqlite"
+	_ "prabogo/internal/adapter/outbound/temporal"
+	_ "prabogo/internal/migration/postgres"
+	_ "prabogo/internal/migration/sqlite"
+)
@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upClientRole, downClientRole)
+}
+
+func upClientRole(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS client_roles (
+		id SERIAL PRIMARY KEY,
+		client_id INTEGER NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+		role VARCHAR(50) NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		UNIQUE (client_id, role)
+	);`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downClientRole(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec(`DROP TABLE client_roles;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package migrations
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+
+	"github.com/pressly/goose/v3"
+)
+
+// The key format as of this migration, kept here so replaying it stores the same data
+// whatever the model hashes today
+const (
+	clientKeyDefaultLabel = "default"
+	clientKeyPrefixLength = 12
+	clientKeySaltBytes    = 16
+)
+
+// clientKey is a hashed key row of this migration
+type clientKey struct {
+	clientID int
+	label    string
+	prefix   string
+	salt     string
+	hash     string
+}
+
+func init() {
+	goose.AddMigrationContext(upClientKey, downClientKey)
+}
+
+func upClientKey(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS client_keys (
+		id SERIAL PRIMARY KEY,
+		client_id INTEGER NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+		label VARCHAR(100) NOT NULL,
+		prefix VARCHAR(16) NOT NULL,
+		salt VARCHAR(64) NOT NULL,
+		hash VARCHAR(64) NOT NULL,
+		expires_at TIMESTAMP,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		UNIQUE (client_id, label)
+	);
+	CREATE INDEX IF NOT EXISTS client_keys_prefix_idx ON client_keys (prefix);`)
+	if err != nil {
+		return err
+	}
+
+	// Existing plaintext keys become the hashed default key of their client
+	rows, err := tx.QueryContext(ctx, `SELECT id, bearer_key FROM clients WHERE bearer_key IS NOT NULL AND bearer_key <> ''`)
+	if err != nil {
+		return err
+	}
+
+	var inputs []clientKey
+	for rows.Next() {
+		var clientID int
+		var bearerKey string
+		if err := rows.Scan(&clientID, &bearerKey); err != nil {
+			rows.Close()
+			return err
+		}
+
+		input, err := hashClientKey(clientID, bearerKey)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		inputs = append(inputs, input)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, input := range inputs {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO client_keys (client_id, label, prefix, salt, hash) VALUES ($1, $2, $3, $4, $5)`,
+			input.clientID, input.label, input.prefix, input.salt, input.hash,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Upserts matched clients by bearer key before, so names could repeat. The oldest
+	// client keeps the name and the others get their id appended, which keeps each
+	// client with its own roles and key.
+	_, err = tx.ExecContext(ctx, `UPDATE clients SET name = LEFT(clients.name, 100 - LENGTH(' #' || clients.id)) || ' #' || clients.id
+	FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY name ORDER BY id) AS position FROM clients WHERE name IS NOT NULL) duplicates
+	WHERE clients.id = duplicates.id AND duplicates.position > 1`)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`ALTER TABLE clients DROP COLUMN bearer_key;
+	ALTER TABLE clients ADD CONSTRAINT clients_name_key UNIQUE (name);`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// hashClientKey stores the prefix and the salted SHA-256 of bearerKey, as the model did
+// when this migration was written
+func hashClientKey(clientID int, bearerKey string) (clientKey, error) {
+	salt := make([]byte, clientKeySaltBytes)
+	if _, err := rand.Read(salt); err != nil {
+		return clientKey{}, err
+	}
+
+	prefix := bearerKey
+	if len(prefix) > clientKeyPrefixLength {
+		prefix = prefix[:clientKeyPrefixLength]
+	}
+
+	key := clientKey{
+		clientID: clientID,
+		label:    clientKeyDefaultLabel,
+		prefix:   prefix,
+		salt:     hex.EncodeToString(salt),
+	}
+	sum := sha256.Sum256([]byte(key.salt + bearerKey))
+	key.hash = hex.EncodeToString(sum[:])
+	return key, nil
+}
+
+func downClientKey(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	// Hashed keys cannot be restored, clients need new keys after a rollback.
+	_, err := tx.Exec(`ALTER TABLE clients DROP CONSTRAINT clients_name_key;
+	ALTER TABLE clients ADD COLUMN bearer_key VARCHAR(255) UNIQUE;
+	DROP TABLE client_keys;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
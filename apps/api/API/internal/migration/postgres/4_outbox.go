@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upOutbox, downOutbox)
+}
+
+func upOutbox(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS outbox_messages (
+		id BIGSERIAL PRIMARY KEY,
+		topic VARCHAR(255) NOT NULL,
+		aggregate_type VARCHAR(100) NOT NULL,
+		aggregate_id VARCHAR(255) NOT NULL,
+		payload TEXT NOT NULL,
+		attempts INTEGER DEFAULT 0 NOT NULL,
+		last_error TEXT DEFAULT '' NOT NULL,
+		available_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		published_at TIMESTAMP,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS outbox_messages_pending_idx ON outbox_messages (aggregate_type, aggregate_id, id) WHERE published_at IS NULL;
+	CREATE INDEX IF NOT EXISTS outbox_messages_published_at_idx ON outbox_messages (published_at) WHERE published_at IS NOT NULL;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downOutbox(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec(`DROP TABLE outbox_messages;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
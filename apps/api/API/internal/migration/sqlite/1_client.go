@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+
+	"prabogo/utils/database"
+)
+
+func init() {
+	database.AddMigration("sqlite", goose.NewGoMigration(1, &goose.GoFunc{RunTx: upClient}, &goose.GoFunc{RunTx: downClient}))
+}
+
+func upClient(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS clients (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name VARCHAR(100) UNIQUE,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+	);`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downClient(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec(`DROP TABLE clients;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
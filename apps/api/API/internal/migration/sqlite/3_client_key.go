@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pressly/goose/v3"
+
+	"prabogo/utils/database"
+)
+
+func init() {
+	database.AddMigration("sqlite", goose.NewGoMigration(3, &goose.GoFunc{RunTx: upClientKey}, &goose.GoFunc{RunTx: downClientKey}))
+}
+
+func upClientKey(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	// Unlike postgres there are no plaintext keys to move, clients never had a bearer_key column.
+	_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS client_keys (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		client_id INTEGER NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+		label VARCHAR(100) NOT NULL,
+		prefix VARCHAR(16) NOT NULL,
+		salt VARCHAR(64) NOT NULL,
+		hash VARCHAR(64) NOT NULL,
+		expires_at TIMESTAMP,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+		UNIQUE (client_id, label)
+	);
+	CREATE INDEX IF NOT EXISTS client_keys_prefix_idx ON client_keys (prefix);`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func downClientKey(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	_, err := tx.Exec(`DROP TABLE client_keys;`)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -2,8 +2,6 @@ package model
 
 import (
 	"time"
-
-	"prabogo/utils"
 )
 
 const (
@@ -14,29 +12,72 @@ const (
 type Client struct {
 	ID int `json:"id" db:"id"`
 	ClientInput
+	// BearerKey is only set on the response that created the client's first key
+	BearerKey string `json:"bearer_key,omitempty" db:"-"`
+	// KeyExpiresAt is the expiry of the key the client was authenticated with
+	KeyExpiresAt *time.Time `json:"key_expires_at,omitempty" db:"-"`
 }
 
 type ClientInput struct {
-	Name      string    `json:"name" db:"name"`
-	BearerKey string    `json:"bearer_key,omitempty" db:"bearer_key"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	Name      string    `json:"name" db:"name" validate:"required,max=100"`
+	Roles     []Role    `json:"roles,omitempty" db:"-" validate:"max=5"`
+	CreatedAt time.Time `json:"created_at" db:"created_at" validate:"server"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at" validate:"server"`
 }
 
+// ClientSortFields are the fields clients can be sorted by
+var ClientSortFields = []string{"id", "name", "created_at", "updated_at"}
+
 type ClientFilter struct {
-	IDs        []int    `json:"ids"`
-	Names      []string `json:"names"`
-	BearerKeys []string `json:"bearer_keys"`
+	IDs   []int    `json:"ids"`
+	Names []string `json:"names"`
+	// NamePrefix matches names starting with the value
+	NamePrefix string `json:"name_prefix"`
+	// NameSearch matches names containing the value, ignoring case
+	NameSearch string    `json:"name_search"`
+	CreatedAt  TimeRange `json:"created_at"`
+	UpdatedAt  TimeRange `json:"updated_at"`
+
+	Sort []Sort     `json:"sort"`
+	Page Pagination `json:"page"`
 }
 
 func ClientPrepare(v *ClientInput) {
 	v.CreatedAt = time.Now()
 	v.UpdatedAt = time.Now()
-	if v.BearerKey == "" {
-		v.BearerKey = utils.GenerateSecureToken(25)
-	}
 }
 
+// IsEmpty reports whether the filter matches every client, sorting and paging aside
 func (c ClientFilter) IsEmpty() bool {
-	return len(c.IDs) == 0 && len(c.Names) == 0 && len(c.BearerKeys) == 0
+	return len(c.IDs) == 0 && len(c.Names) == 0 && c.NamePrefix == "" && c.NameSearch == "" &&
+		c.CreatedAt.IsEmpty() && c.UpdatedAt.IsEmpty()
+}
+
+// ClientCursor returns the cursor continuing after client in a list sorted by sorts,
+// which must be normalized
+func ClientCursor(client Client, sorts []Sort) (string, error) {
+	values := make([]any, 0, len(sorts))
+	for _, sort := range sorts {
+		switch sort.Field {
+		case "id":
+			values = append(values, client.ID)
+		case "name":
+			values = append(values, client.Name)
+		case "created_at":
+			values = append(values, client.CreatedAt)
+		case "updated_at":
+			values = append(values, client.UpdatedAt)
+		}
+	}
+	return EncodeCursor(values...)
+}
+
+// RedactBearerKeys returns a copy of clients without bearer keys, for logging
+func RedactBearerKeys(clients []Client) []Client {
+	redacted := make([]Client, len(clients))
+	for i := range clients {
+		redacted[i] = clients[i]
+		redacted[i].BearerKey = ""
+	}
+	return redacted
 }
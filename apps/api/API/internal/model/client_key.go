@@ -0,0 +1,94 @@
+package model
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"time"
+
+	"prabogo/utils"
+)
+
+const (
+	DefaultClientKeyLabel = "default"
+	bearerKeyBytes        = 32
+	bearerKeyPrefixLength = 12
+	bearerKeySaltBytes    = 16
+)
+
+type ClientKey struct {
+	ID int `json:"id" db:"id"`
+	ClientKeyInput
+	// BearerKey is only set on the response that created the key
+	BearerKey string `json:"bearer_key,omitempty" db:"-"`
+}
+
+type ClientKeyInput struct {
+	ClientID  int        `json:"client_id" db:"client_id" validate:"required,min=1"`
+	Label     string     `json:"label" db:"label" validate:"max=100"`
+	Prefix    string     `json:"prefix" db:"prefix" validate:"server"`
+	Salt      string     `json:"-" db:"salt" validate:"server"`
+	Hash      string     `json:"-" db:"hash" validate:"server"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at" validate:"server"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at" validate:"server"`
+}
+
+type ClientKeyFilter struct {
+	IDs       []int    `json:"ids"`
+	ClientIDs []int    `json:"client_ids"`
+	Labels    []string `json:"labels"`
+	Prefixes  []string `json:"prefixes"`
+}
+
+// GenerateBearerKey returns a new random bearer key
+func GenerateBearerKey() string {
+	return utils.GenerateSecureToken(bearerKeyBytes)
+}
+
+// ClientCacheKey keys cached clients by a hash of the bearer key, so the key never
+// reaches the cache
+func ClientCacheKey(bearerKey string) string {
+	sum := sha256.Sum256([]byte(bearerKey))
+	return "client:" + hex.EncodeToString(sum[:])
+}
+
+// BearerKeyPrefix returns the lookup prefix of a bearer key
+func BearerKeyPrefix(bearerKey string) string {
+	if len(bearerKey) < bearerKeyPrefixLength {
+		return bearerKey
+	}
+	return bearerKey[:bearerKeyPrefixLength]
+}
+
+// ClientKeyPrepare stores the prefix and the salted hash of bearerKey on the input,
+// the bearer key itself is never stored
+func ClientKeyPrepare(v *ClientKeyInput, bearerKey string) {
+	if v.Label == "" {
+		v.Label = DefaultClientKeyLabel
+	}
+	v.Prefix = BearerKeyPrefix(bearerKey)
+	v.Salt = utils.GenerateSecureToken(bearerKeySaltBytes)
+	v.Hash = hashBearerKey(v.Salt, bearerKey)
+	v.CreatedAt = time.Now()
+	v.UpdatedAt = time.Now()
+}
+
+// Matches reports whether bearerKey is the key this hash was made from
+func (v ClientKeyInput) Matches(bearerKey string) bool {
+	hash := hashBearerKey(v.Salt, bearerKey)
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(v.Hash)) == 1
+}
+
+func (v ClientKeyInput) IsExpired(now time.Time) bool {
+	return v.ExpiresAt != nil && !now.Before(*v.ExpiresAt)
+}
+
+func (c ClientKeyFilter) IsEmpty() bool {
+	return len(c.IDs) == 0 && len(c.ClientIDs) == 0 && len(c.Labels) == 0 && len(c.Prefixes) == 0
+}
+
+func hashBearerKey(salt, bearerKey string) string {
+	sum := sha256.Sum256([]byte(salt + bearerKey))
+	return hex.EncodeToString(sum[:])
+}
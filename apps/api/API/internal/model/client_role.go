@@ -0,0 +1,141 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"prabogo/utils/activity"
+)
+
+type Role string
+
+const (
+	RoleOwner    Role = "owner"
+	RoleManager  Role = "manager"
+	RoleStaff    Role = "staff"
+	RoleTenant   Role = "tenant"
+	RoleInternal Role = "internal"
+)
+
+type Permission string
+
+const (
+	PermissionClientRead   Permission = "client:read"
+	PermissionClientWrite  Permission = "client:write"
+	PermissionClientDelete Permission = "client:delete"
+	PermissionResourceRead Permission = "resource:read"
+	PermissionRoleWrite    Permission = "role:write"
+)
+
+var ErrPermissionDenied = errors.New("permission denied")
+
+// RolePermissions lists the permissions granted to each role
+var RolePermissions = map[Role][]Permission{
+	RoleOwner: {
+		PermissionClientRead,
+		PermissionClientWrite,
+		PermissionClientDelete,
+		PermissionResourceRead,
+		PermissionRoleWrite,
+	},
+	RoleManager: {
+		PermissionClientRead,
+		PermissionClientWrite,
+		PermissionResourceRead,
+	},
+	RoleStaff: {
+		PermissionClientRead,
+		PermissionResourceRead,
+	},
+	RoleTenant: {
+		PermissionResourceRead,
+	},
+	RoleInternal: {
+		PermissionClientRead,
+		PermissionClientWrite,
+		PermissionClientDelete,
+		PermissionResourceRead,
+		PermissionRoleWrite,
+	},
+}
+
+type ClientRole struct {
+	ID int `json:"id" db:"id"`
+	ClientRoleInput
+}
+
+type ClientRoleInput struct {
+	ClientID  int       `json:"client_id" db:"client_id"`
+	Role      Role      `json:"role" db:"role"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type ClientRoleFilter struct {
+	IDs       []int  `json:"ids"`
+	ClientIDs []int  `json:"client_ids"`
+	Roles     []Role `json:"roles"`
+}
+
+func ClientRolePrepare(v *ClientRoleInput) {
+	v.CreatedAt = time.Now()
+	v.UpdatedAt = time.Now()
+}
+
+func (c ClientRoleFilter) IsEmpty() bool {
+	return len(c.IDs) == 0 && len(c.ClientIDs) == 0 && len(c.Roles) == 0
+}
+
+func (r Role) IsValid() bool {
+	_, ok := RolePermissions[r]
+	return ok
+}
+
+// HasPermission reports whether any of the roles grants the permission
+func HasPermission(roles []Role, permission Permission) bool {
+	for _, role := range roles {
+		for _, granted := range RolePermissions[role] {
+			if granted == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RolesFromStrings converts role names, dropping unknown roles
+func RolesFromStrings(names []string) []Role {
+	roles := []Role{}
+	for _, name := range names {
+		if role := Role(name); role.IsValid() {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// RolesToStrings converts roles to their names
+func RolesToStrings(roles []Role) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, string(role))
+	}
+	return names
+}
+
+// CheckPermission returns ErrPermissionDenied when the caller in ctx lacks the permission.
+// Contexts without caller roles come from trusted entry points such as commands,
+// message subscribers and workflows and are allowed.
+func CheckPermission(ctx context.Context, permission Permission) error {
+	names, ok := activity.GetRoles(ctx)
+	if !ok {
+		return nil
+	}
+
+	if !HasPermission(RolesFromStrings(names), permission) {
+		return ErrPermissionDenied
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/palantir/stacktrace"
+)
+
+// Error codes classify failures. Domain errors carry them with stacktrace.NewErrorWithCode
+// or stacktrace.PropagateWithCode, and callers read them with ErrorCodeOf.
+const (
+	ErrorCodeValidation stacktrace.ErrorCode = iota + 1
+	ErrorCodeNotFound
+	ErrorCodeConflict
+	ErrorCodeUnauthorized
+	ErrorCodeForbidden
+	ErrorCodeUnavailable
+)
+
+// errorNames are the stable machine readable codes returned in Response.Code
+var errorNames = map[stacktrace.ErrorCode]string{
+	ErrorCodeValidation:   "validation_failed",
+	ErrorCodeNotFound:     "not_found",
+	ErrorCodeConflict:     "conflict",
+	ErrorCodeUnauthorized: "unauthorized",
+	ErrorCodeForbidden:    "forbidden",
+	ErrorCodeUnavailable:  "unavailable",
+	stacktrace.NoCode:     "internal",
+}
+
+// ErrConflict is wrapped by database adapters when a write violates a unique constraint
+var ErrConflict = errors.New("conflicts with an existing record")
+
+// ErrNotConfigured is wrapped by outbound ports whose driver is none or not set
+var ErrNotConfigured = errors.New("not configured")
+
+// ErrorCodeOf returns the code of err. Errors without a code are classified by their
+// root cause, anything else is internal and reported as stacktrace.NoCode.
+func ErrorCodeOf(err error) stacktrace.ErrorCode {
+	if code := stacktrace.GetCode(err); code != stacktrace.NoCode {
+		return code
+	}
+
+	root := stacktrace.RootCause(err)
+	var netErr net.Error
+	switch {
+	case root == ErrPermissionDenied:
+		return ErrorCodeForbidden
+	case root == ErrInvalidCursor:
+		return ErrorCodeValidation
+	case errors.Is(root, ErrConflict):
+		return ErrorCodeConflict
+	case errors.Is(root, ErrNotConfigured):
+		return ErrorCodeUnavailable
+	case errors.Is(root, context.DeadlineExceeded), errors.Is(root, context.Canceled),
+		errors.Is(root, driver.ErrBadConn), errors.Is(root, sql.ErrConnDone), errors.As(root, &netErr):
+		return ErrorCodeUnavailable
+	}
+
+	return stacktrace.NoCode
+}
+
+// ErrorName returns the machine readable code of err
+func ErrorName(err error) string {
+	return errorNames[ErrorCodeOf(err)]
+}
+
+// ErrorMessage returns a message of err that is safe to show to callers. Internal and
+// unavailable errors get a generic message, their details only belong in the logs.
+func ErrorMessage(err error) string {
+	root := stacktrace.RootCause(err)
+	switch ErrorCodeOf(err) {
+	case stacktrace.NoCode:
+		return "internal error"
+	case ErrorCodeUnavailable:
+		return "service unavailable"
+	case ErrorCodeConflict:
+		if errors.Is(root, ErrConflict) {
+			return ErrConflict.Error()
+		}
+	}
+
+	return fmt.Sprintf("%#s", root)
+}
+
+// IsTemporary reports whether err may pass when the same operation is retried
+func IsTemporary(err error) bool {
+	return ErrorCodeOf(err) == ErrorCodeUnavailable
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+
+	// MaxPageLimit caps the rows returned by one page
+	MaxPageLimit = 1000
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Sort orders a list by one field, lists are sorted by every Sort in turn and then by id
+type Sort struct {
+	Field     string `json:"field"`
+	Direction string `json:"direction"`
+}
+
+// Pagination asks for one page of a list. A zero Limit returns the whole list. Cursor
+// continues after the last row of the previous page and takes precedence over Offset.
+type Pagination struct {
+	Limit  int    `json:"limit" validate:"min=0"`
+	Offset int    `json:"offset" validate:"min=0"`
+	Cursor string `json:"cursor"`
+}
+
+// PageInfo describes the page returned for a Pagination
+type PageInfo struct {
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset,omitempty"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// TimeRange matches times from From up to but excluding To, either bound may be nil
+type TimeRange struct {
+	From *time.Time `json:"from"`
+	To   *time.Time `json:"to"`
+}
+
+func (r TimeRange) IsEmpty() bool {
+	return r.From == nil && r.To == nil
+}
+
+// NormalizeSort checks sorts against the sortable fields, defaults the direction to
+// ascending and appends id so that every row has a unique position
+func NormalizeSort(sorts []Sort, fields ...string) ([]Sort, error) {
+	result := make([]Sort, 0, len(sorts)+1)
+	hasID := false
+	for _, sort := range sorts {
+		if !slices.Contains(fields, sort.Field) {
+			return nil, fmt.Errorf("cannot sort by %q", sort.Field)
+		}
+
+		sort.Direction = strings.ToLower(sort.Direction)
+		switch sort.Direction {
+		case "":
+			sort.Direction = SortAsc
+		case SortAsc, SortDesc:
+		default:
+			return nil, fmt.Errorf("invalid sort direction %q", sort.Direction)
+		}
+
+		hasID = hasID || sort.Field == "id"
+		result = append(result, sort)
+	}
+
+	if !hasID {
+		result = append(result, Sort{Field: "id", Direction: SortAsc})
+	}
+
+	return result, nil
+}
+
+// EncodeCursor returns an opaque cursor holding the sort values of a row
+func EncodeCursor(values ...any) (string, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor returns the raw sort values of a cursor made by EncodeCursor
+func DecodeCursor(cursor string) ([]json.RawMessage, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var values []json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return values, nil
+}
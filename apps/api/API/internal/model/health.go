@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+)
+
+// ComponentHealth is the result of checking one dependency
+type ComponentHealth struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	// Error is why the check failed, it is logged but not shown to unauthenticated probes
+	Error string `json:"-"`
+}
+
+// Health is the readiness of the service, it is up when every component is up
+type Health struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentHealth `json:"components,omitempty"`
+	// Inbounds are the servers, subscribers and workers the process runs
+	Inbounds  map[string]ComponentHealth `json:"inbounds,omitempty"`
+	CheckedAt time.Time                  `json:"checked_at"`
+}
+
+func (h Health) IsUp() bool {
+	return h.Status == HealthStatusUp
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ClientAggregate is the aggregate type of the messages about a client
+const ClientAggregate = "client"
+
+// OutboxMessage is a message stored with the write that caused it, the relay publishes
+// it once the write is committed
+type OutboxMessage struct {
+	ID int `json:"id" db:"id"`
+	OutboxMessageInput
+	Attempts int `json:"attempts" db:"attempts"`
+	// LastError is why the last publish failed
+	LastError   string     `json:"last_error" db:"last_error"`
+	PublishedAt *time.Time `json:"published_at,omitempty" db:"published_at"`
+}
+
+type OutboxMessageInput struct {
+	// Topic is the exchange the message is published to
+	Topic string `json:"topic" db:"topic"`
+	// AggregateType and AggregateID name what the message is about, the messages of one
+	// aggregate are published in the order they were written
+	AggregateType string `json:"aggregate_type" db:"aggregate_type"`
+	AggregateID   string `json:"aggregate_id" db:"aggregate_id"`
+	// Payload is the JSON body of the message
+	Payload string `json:"payload" db:"payload"`
+	// AvailableAt is when the message may be published, it moves forward on every failure
+	AvailableAt time.Time `json:"available_at" db:"available_at"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// NewOutboxMessage returns the outbox input publishing payload as JSON to topic
+func NewOutboxMessage(topic, aggregateType, aggregateID string, payload any) (OutboxMessageInput, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return OutboxMessageInput{}, err
+	}
+
+	// The relay compares AvailableAt with the time in UTC
+	now := time.Now().UTC()
+	return OutboxMessageInput{
+		Topic:         topic,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Payload:       string(body),
+		AvailableAt:   now,
+		CreatedAt:     now,
+	}, nil
+}
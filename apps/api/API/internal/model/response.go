@@ -1,7 +1,18 @@
 package model
 
+import "prabogo/utils/validate"
+
 type Response struct {
 	Success bool   `json:"success"`
+	Code    string `json:"code,omitempty"`
 	Error   string `json:"error,omitempty"`
-	Data    any    `json:"data,omitempty"`
+	// Details lists the invalid fields of a validation error
+	Details []validate.FieldError `json:"details,omitempty"`
+	Data    any                   `json:"data,omitempty"`
+	Meta    *Meta                 `json:"meta,omitempty"`
+}
+
+// Meta describes the list returned in Data
+type Meta struct {
+	Page *PageInfo `json:"page,omitempty"`
 }
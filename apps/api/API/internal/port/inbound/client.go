@@ -1,9 +1,14 @@
 package inbound_port
 
+import "context"
+
 type ClientHttpPort interface {
 	Upsert(a any) error
 	Find(a any) error
 	Delete(a any) error
+	UpsertKey(a any) error
+	FindKeys(a any) error
+	DeleteKeys(a any) error
 }
 
 type ClientMessagePort interface {
@@ -16,5 +21,6 @@ type ClientCommandPort interface {
 }
 
 type ClientWorkflowPort interface {
-	Upsert()
+	// Upsert runs the upsert client worker until ctx is done, or returns why it could not
+	Upsert(ctx context.Context) error
 }
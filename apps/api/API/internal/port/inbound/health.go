@@ -0,0 +1,8 @@
+package inbound_port
+
+type HealthHttpPort interface {
+	// Live reports that the process is serving, without checking dependencies
+	Live(a any) error
+	// Ready reports whether the dependencies can be used
+	Ready(a any) error
+}
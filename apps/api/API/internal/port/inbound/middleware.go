@@ -1,6 +1,11 @@
 package inbound_port
 
+import "prabogo/internal/model"
+
 type MiddlewareHttpPort interface {
 	InternalAuth(a any) error
 	ClientAuth(a any) error
+	Authorize(a any, permissions ...model.Permission) error
+	RateLimit(a any, group string) error
+	RequestTimeout(a any) error
 }
@@ -3,5 +3,6 @@ package inbound_port
 type HttpPort interface {
 	Middleware() MiddlewareHttpPort
 	Ping() PingHttpPort
+	Health() HealthHttpPort
 	Client() ClientHttpPort
 }
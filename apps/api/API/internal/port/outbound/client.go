@@ -1,24 +1,43 @@
 package outbound_port
 
-import "prabogo/internal/model"
+import (
+	"context"
+	"errors"
+	"time"
+
+	"prabogo/internal/model"
+)
+
+// ErrClientUnknown is returned by ClientCachePort.Get for bearer keys cached as unknown
+var ErrClientUnknown = errors.New("client is cached as unknown")
 
 //go:generate mockgen -source=client.go -destination=./../../../tests/mocks/port/mock_client.go
 type ClientDatabasePort interface {
-	Upsert(datas []model.ClientInput) error
-	FindByFilter(filter model.ClientFilter, lock bool) ([]model.Client, error)
-	DeleteByFilter(filter model.ClientFilter) error
-	IsExists(bearerKey string) (bool, error)
+	Upsert(ctx context.Context, datas []model.ClientInput) error
+	// FindByFilter returns the clients matching filter in the order and page it asks for
+	FindByFilter(ctx context.Context, filter model.ClientFilter, lock bool) ([]model.Client, error)
+	// CountByFilter counts the clients matching filter, ignoring its sort and page
+	CountByFilter(ctx context.Context, filter model.ClientFilter) (int, error)
+	DeleteByFilter(ctx context.Context, filter model.ClientFilter) error
 }
 
 type ClientMessagePort interface {
-	PublishUpsert(datas []model.ClientInput) error
+	PublishUpsert(ctx context.Context, datas []model.ClientInput) error
 }
 
 type ClientCachePort interface {
-	Set(data model.Client) error
-	Get(bearerKey string) (model.Client, error)
+	// Version changes whenever cached clients are evicted, it is read before loading the
+	// client given to Set
+	Version(ctx context.Context) (int64, error)
+	// Set caches data unless clients were evicted since version, so a lookup that read
+	// the database before a change cannot cache the replaced client after its eviction
+	Set(ctx context.Context, bearerKey string, data model.Client, version int64) error
+	SetUnknown(ctx context.Context, bearerKey string, ttl time.Duration) error
+	Get(ctx context.Context, bearerKey string) (model.Client, error)
+	Delete(ctx context.Context, bearerKey string) error
+	DeleteByClientIDs(ctx context.Context, clientIDs []int) error
 }
 
 type ClientWorkflowPort interface {
-	StartUpsert(data model.ClientInput) error
+	StartUpsert(ctx context.Context, data model.ClientInput) error
 }
@@ -0,0 +1,14 @@
+package outbound_port
+
+import (
+	"context"
+
+	"prabogo/internal/model"
+)
+
+//go:generate mockgen -source=client_key.go -destination=./../../../tests/mocks/port/mock_client_key.go
+type ClientKeyDatabasePort interface {
+	Upsert(ctx context.Context, datas []model.ClientKeyInput) error
+	FindByFilter(ctx context.Context, filter model.ClientKeyFilter) ([]model.ClientKey, error)
+	DeleteByFilter(ctx context.Context, filter model.ClientKeyFilter) error
+}
@@ -0,0 +1,14 @@
+package outbound_port
+
+import (
+	"context"
+
+	"prabogo/internal/model"
+)
+
+//go:generate mockgen -source=client_role.go -destination=./../../../tests/mocks/port/mock_client_role.go
+type ClientRoleDatabasePort interface {
+	Upsert(ctx context.Context, datas []model.ClientRoleInput) error
+	FindByFilter(ctx context.Context, filter model.ClientRoleFilter) ([]model.ClientRole, error)
+	DeleteByFilter(ctx context.Context, filter model.ClientRoleFilter) error
+}
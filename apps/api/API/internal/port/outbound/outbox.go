@@ -0,0 +1,26 @@
+package outbound_port
+
+import (
+	"context"
+	"time"
+
+	"prabogo/internal/model"
+)
+
+//go:generate mockgen -source=outbox.go -destination=./../../../tests/mocks/port/mock_outbox.go
+type OutboxDatabasePort interface {
+	Insert(ctx context.Context, datas []model.OutboxMessageInput) error
+	// FindPending returns up to limit unpublished messages available at now, oldest first.
+	// Only the oldest unpublished message of an aggregate is returned, so an aggregate is
+	// published in order. Within a transaction the rows are locked, other relays skip them.
+	FindPending(ctx context.Context, now time.Time, limit int) ([]model.OutboxMessage, error)
+	MarkPublished(ctx context.Context, ids []int, publishedAt time.Time) error
+	// MarkFailed counts a failed attempt and holds the message until availableAt
+	MarkFailed(ctx context.Context, id int, reason string, availableAt time.Time) error
+	// DeletePublished deletes the messages published before before
+	DeletePublished(ctx context.Context, before time.Time) error
+}
+
+type OutboxMessagePort interface {
+	Publish(ctx context.Context, message model.OutboxMessage) error
+}
@@ -1,6 +1,22 @@
 package outbound_port
 
+import (
+	"context"
+	"fmt"
+
+	"prabogo/utils/ratelimit"
+	"prabogo/utils/redis"
+)
+
+// ErrCacheMiss is returned by cache ports when a key is not cached or has expired. It
+// wraps redis.Nil, so a miss reads the same from every driver.
+var ErrCacheMiss = fmt.Errorf("cache miss: %w", redis.Nil)
+
 //go:generate mockgen -source=registry_cache.go -destination=./../../../tests/mocks/port/mock_registry_cache.go
 type CachePort interface {
 	Client() ClientCachePort
+	// RateLimit counts rate limit hits, a shared cache shares them between instances
+	RateLimit() ratelimit.Store
+	// Ping checks that the cache can be reached
+	Ping(ctx context.Context) error
 }
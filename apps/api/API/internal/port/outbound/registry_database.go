@@ -1,18 +1,28 @@
 package outbound_port
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+)
 
 //go:generate mockgen -source=registry_database.go -destination=./../../../tests/mocks/port/mock_registry_database.go
 type InTransaction func(repoRegistry DatabasePort) (interface{}, error)
 
 type DatabasePort interface {
+	ClientKey() ClientKeyDatabasePort
+	ClientRole() ClientRoleDatabasePort
 	Client() ClientDatabasePort
-	DoInTransaction(txFunc InTransaction) (out interface{}, err error)
+	// Outbox stores the messages to publish, write them with the transaction of the
+	// change they announce
+	Outbox() OutboxDatabasePort
+	DoInTransaction(ctx context.Context, txFunc InTransaction) (out interface{}, err error)
+	// Ping checks that the database can run a query
+	Ping(ctx context.Context) error
 }
 
 type DatabaseExecutor interface {
-	Exec(query string, args ...interface{}) (sql.Result, error)
-	Prepare(string) (*sql.Stmt, error)
-	Query(string, ...interface{}) (*sql.Rows, error)
-	QueryRow(string, ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
@@ -1,6 +1,11 @@
 package outbound_port
 
+import "context"
+
 //go:generate mockgen -source=registry_message.go -destination=./../../../tests/mocks/port/mock_registry_message.go
 type MessagePort interface {
 	Client() ClientMessagePort
+	Outbox() OutboxMessagePort
+	// Ping checks that the broker connection is open
+	Ping(ctx context.Context) error
 }
@@ -1,6 +1,10 @@
 package outbound_port
 
+import "context"
+
 //go:generate mockgen -source=registry_workflow.go -destination=./../../../tests/mocks/port/mock_registry_workflow.go
 type WorkflowPort interface {
 	Client() ClientWorkflowPort
+	// Ping checks that the workflow service is healthy
+	Ping(ctx context.Context) error
 }
@@ -0,0 +1,485 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/internal/model"
+	outbound_port "prabogo/internal/port/outbound"
+)
+
+// DatabasePort checks that a driver behaves like every other DatabasePort driver.
+// newPort is called for every case and must return a port over a migrated, empty database.
+func DatabasePort(t *testing.T, driver string, newPort func() outbound_port.DatabasePort) {
+	Convey("DatabasePort contract for "+driver, t, func() {
+		ctx := context.Background()
+		port := newPort()
+
+		now := time.Now().UTC().Truncate(time.Second)
+		clientA := upsertClient(ctx, port, "Client A", now)
+		clientB := upsertClient(ctx, port, "Client B", now)
+
+		Convey("Client", func() {
+			Convey("Upsert on an existing name keeps the id and updates updated_at", func() {
+				later := now.Add(time.Hour)
+				input := model.ClientInput{Name: "Client A", CreatedAt: later, UpdatedAt: later}
+				So(port.Client().Upsert(ctx, []model.ClientInput{input}), ShouldBeNil)
+
+				clients, err := port.Client().FindByFilter(ctx, model.ClientFilter{Names: []string{"Client A"}}, false)
+				So(err, ShouldBeNil)
+				So(clients, ShouldHaveLength, 1)
+				So(clients[0].ID, ShouldEqual, clientA.ID)
+				So(clients[0].CreatedAt.Equal(now), ShouldBeTrue)
+				So(clients[0].UpdatedAt.Equal(later), ShouldBeTrue)
+			})
+
+			Convey("Upsert inserts several clients at once", func() {
+				inputs := []model.ClientInput{
+					{Name: "Client C", CreatedAt: now, UpdatedAt: now},
+					{Name: "Client D", CreatedAt: now, UpdatedAt: now},
+				}
+				So(port.Client().Upsert(ctx, inputs), ShouldBeNil)
+
+				clients, err := port.Client().FindByFilter(ctx, model.ClientFilter{}, false)
+				So(err, ShouldBeNil)
+				So(clients, ShouldHaveLength, 4)
+			})
+
+			Convey("FindByFilter without fields returns every client", func() {
+				clients, err := port.Client().FindByFilter(ctx, model.ClientFilter{}, false)
+				So(err, ShouldBeNil)
+				So(clientNames(clients), ShouldResemble, []string{"Client A", "Client B"})
+			})
+
+			Convey("FindByFilter by ids", func() {
+				clients, err := port.Client().FindByFilter(ctx, model.ClientFilter{IDs: []int{clientB.ID}}, false)
+				So(err, ShouldBeNil)
+				So(clientNames(clients), ShouldResemble, []string{"Client B"})
+			})
+
+			Convey("FindByFilter by names", func() {
+				clients, err := port.Client().FindByFilter(ctx, model.ClientFilter{Names: []string{"Client A", "Unknown"}}, false)
+				So(err, ShouldBeNil)
+				So(clientNames(clients), ShouldResemble, []string{"Client A"})
+			})
+
+			Convey("FindByFilter fields are combined", func() {
+				clients, err := port.Client().FindByFilter(ctx, model.ClientFilter{IDs: []int{clientA.ID}, Names: []string{"Client B"}}, false)
+				So(err, ShouldBeNil)
+				So(clients, ShouldBeEmpty)
+			})
+
+			Convey("FindByFilter without matches returns an empty result", func() {
+				clients, err := port.Client().FindByFilter(ctx, model.ClientFilter{IDs: []int{-1}}, false)
+				So(err, ShouldBeNil)
+				So(clients, ShouldNotBeNil)
+				So(clients, ShouldBeEmpty)
+			})
+
+			Convey("FindByFilter with lock inside a transaction", func() {
+				out, err := port.DoInTransaction(ctx, func(tx outbound_port.DatabasePort) (interface{}, error) {
+					return tx.Client().FindByFilter(ctx, model.ClientFilter{IDs: []int{clientA.ID}}, true)
+				})
+				So(err, ShouldBeNil)
+				So(clientNames(out.([]model.Client)), ShouldResemble, []string{"Client A"})
+			})
+
+			Convey("FindByFilter operators, sorting and paging", func() {
+				later, latest := now.Add(time.Hour), now.Add(2*time.Hour)
+				inputs := []model.ClientInput{
+					{Name: "Other 50%", CreatedAt: later, UpdatedAt: later},
+					{Name: "other_b", CreatedAt: latest, UpdatedAt: latest},
+				}
+				So(port.Client().Upsert(ctx, inputs), ShouldBeNil)
+
+				find := func(filter model.ClientFilter) []string {
+					clients, err := port.Client().FindByFilter(ctx, filter, false)
+					So(err, ShouldBeNil)
+					names := []string{}
+					for _, client := range clients {
+						names = append(names, client.Name)
+					}
+					return names
+				}
+
+				Convey("by name prefix", func() {
+					So(find(model.ClientFilter{NamePrefix: "Client", Sort: []model.Sort{{Field: "name"}}}), ShouldResemble, []string{"Client A", "Client B"})
+					So(find(model.ClientFilter{NamePrefix: "Other 5"}), ShouldResemble, []string{"Other 50%"})
+				})
+
+				Convey("by name search ignoring case and wildcards", func() {
+					So(find(model.ClientFilter{NameSearch: "THER", Sort: []model.Sort{{Field: "name"}}}), ShouldResemble, []string{"Other 50%", "other_b"})
+					So(find(model.ClientFilter{NameSearch: "50%"}), ShouldResemble, []string{"Other 50%"})
+					So(find(model.ClientFilter{NameSearch: "r_"}), ShouldResemble, []string{"other_b"})
+				})
+
+				Convey("by time range", func() {
+					So(find(model.ClientFilter{CreatedAt: model.TimeRange{From: &later}}), ShouldResemble, []string{"Other 50%", "other_b"})
+					So(find(model.ClientFilter{UpdatedAt: model.TimeRange{From: &later, To: &latest}}), ShouldResemble, []string{"Other 50%"})
+				})
+
+				Convey("sorted by several fields", func() {
+					sorts := []model.Sort{{Field: "created_at", Direction: model.SortDesc}, {Field: "name", Direction: model.SortDesc}}
+					So(find(model.ClientFilter{Sort: sorts}), ShouldResemble, []string{"other_b", "Other 50%", "Client B", "Client A"})
+				})
+
+				Convey("paged by offset", func() {
+					page := model.Pagination{Limit: 2, Offset: 1}
+					So(find(model.ClientFilter{Page: page}), ShouldResemble, []string{"Client B", "Other 50%"})
+				})
+
+				Convey("paged by cursor", func() {
+					sorts, err := model.NormalizeSort([]model.Sort{{Field: "created_at", Direction: model.SortDesc}}, model.ClientSortFields...)
+					So(err, ShouldBeNil)
+
+					clients, err := port.Client().FindByFilter(ctx, model.ClientFilter{Sort: sorts, Page: model.Pagination{Limit: 3}}, false)
+					So(err, ShouldBeNil)
+					So(clients, ShouldHaveLength, 3)
+					cursor, err := model.ClientCursor(clients[1], sorts)
+					So(err, ShouldBeNil)
+
+					So(find(model.ClientFilter{Sort: sorts, Page: model.Pagination{Limit: 3, Cursor: cursor}}), ShouldResemble, []string{"Client A", "Client B"})
+				})
+
+				Convey("counted without sort and page", func() {
+					count, err := port.Client().CountByFilter(ctx, model.ClientFilter{NameSearch: "other", Page: model.Pagination{Limit: 1}})
+					So(err, ShouldBeNil)
+					So(count, ShouldEqual, 2)
+				})
+			})
+
+			Convey("DeleteByFilter deletes only the matching clients", func() {
+				So(port.Client().DeleteByFilter(ctx, model.ClientFilter{Names: []string{"Client A"}}), ShouldBeNil)
+
+				clients, err := port.Client().FindByFilter(ctx, model.ClientFilter{}, false)
+				So(err, ShouldBeNil)
+				So(clientNames(clients), ShouldResemble, []string{"Client B"})
+			})
+
+			Convey("DeleteByFilter without matches is not an error", func() {
+				So(port.Client().DeleteByFilter(ctx, model.ClientFilter{IDs: []int{-1}}), ShouldBeNil)
+
+				clients, err := port.Client().FindByFilter(ctx, model.ClientFilter{}, false)
+				So(err, ShouldBeNil)
+				So(clients, ShouldHaveLength, 2)
+			})
+
+			Convey("DeleteByFilter removes the roles and keys of the client", func() {
+				upsertRole(ctx, port, clientA.ID, model.RoleOwner, now)
+				upsertKey(ctx, port, clientA.ID, "default", nil, now)
+
+				So(port.Client().DeleteByFilter(ctx, model.ClientFilter{IDs: []int{clientA.ID}}), ShouldBeNil)
+
+				roles, err := port.ClientRole().FindByFilter(ctx, model.ClientRoleFilter{ClientIDs: []int{clientA.ID}})
+				So(err, ShouldBeNil)
+				So(roles, ShouldBeEmpty)
+
+				keys, err := port.ClientKey().FindByFilter(ctx, model.ClientKeyFilter{ClientIDs: []int{clientA.ID}})
+				So(err, ShouldBeNil)
+				So(keys, ShouldBeEmpty)
+			})
+		})
+
+		Convey("ClientRole", func() {
+			upsertRole(ctx, port, clientA.ID, model.RoleOwner, now)
+			upsertRole(ctx, port, clientA.ID, model.RoleStaff, now)
+			upsertRole(ctx, port, clientB.ID, model.RoleStaff, now)
+
+			Convey("Upsert on an existing client and role keeps one row", func() {
+				upsertRole(ctx, port, clientA.ID, model.RoleOwner, now.Add(time.Hour))
+
+				roles, err := port.ClientRole().FindByFilter(ctx, model.ClientRoleFilter{ClientIDs: []int{clientA.ID}, Roles: []model.Role{model.RoleOwner}})
+				So(err, ShouldBeNil)
+				So(roles, ShouldHaveLength, 1)
+				So(roles[0].UpdatedAt.Equal(now.Add(time.Hour)), ShouldBeTrue)
+			})
+
+			Convey("FindByFilter by ids", func() {
+				roles, err := port.ClientRole().FindByFilter(ctx, model.ClientRoleFilter{ClientIDs: []int{clientB.ID}})
+				So(err, ShouldBeNil)
+				So(roles, ShouldHaveLength, 1)
+
+				roles, err = port.ClientRole().FindByFilter(ctx, model.ClientRoleFilter{IDs: []int{roles[0].ID}})
+				So(err, ShouldBeNil)
+				So(roles, ShouldHaveLength, 1)
+				So(roles[0].ClientID, ShouldEqual, clientB.ID)
+			})
+
+			Convey("FindByFilter by client ids", func() {
+				roles, err := port.ClientRole().FindByFilter(ctx, model.ClientRoleFilter{ClientIDs: []int{clientA.ID}})
+				So(err, ShouldBeNil)
+				So(roles, ShouldHaveLength, 2)
+			})
+
+			Convey("FindByFilter by roles", func() {
+				roles, err := port.ClientRole().FindByFilter(ctx, model.ClientRoleFilter{Roles: []model.Role{model.RoleStaff}})
+				So(err, ShouldBeNil)
+				So(roles, ShouldHaveLength, 2)
+			})
+
+			Convey("DeleteByFilter deletes only the matching roles", func() {
+				So(port.ClientRole().DeleteByFilter(ctx, model.ClientRoleFilter{ClientIDs: []int{clientA.ID}}), ShouldBeNil)
+
+				roles, err := port.ClientRole().FindByFilter(ctx, model.ClientRoleFilter{})
+				So(err, ShouldBeNil)
+				So(roles, ShouldHaveLength, 1)
+				So(roles[0].ClientID, ShouldEqual, clientB.ID)
+			})
+		})
+
+		Convey("ClientKey", func() {
+			expiresAt := now.Add(24 * time.Hour)
+			defaultKey := upsertKey(ctx, port, clientA.ID, "default", nil, now)
+			rotationKey := upsertKey(ctx, port, clientA.ID, "rotation", &expiresAt, now)
+			upsertKey(ctx, port, clientB.ID, "default", nil, now)
+
+			Convey("Upsert on an existing client and label replaces the key", func() {
+				replacement := upsertKey(ctx, port, clientA.ID, "default", nil, now)
+
+				keys, err := port.ClientKey().FindByFilter(ctx, model.ClientKeyFilter{ClientIDs: []int{clientA.ID}, Labels: []string{"default"}})
+				So(err, ShouldBeNil)
+				So(keys, ShouldHaveLength, 1)
+				So(keys[0].Matches(replacement), ShouldBeTrue)
+				So(keys[0].Matches(defaultKey), ShouldBeFalse)
+			})
+
+			Convey("Keys are stored hashed and keep their expiry", func() {
+				keys, err := port.ClientKey().FindByFilter(ctx, model.ClientKeyFilter{ClientIDs: []int{clientA.ID}, Labels: []string{"rotation"}})
+				So(err, ShouldBeNil)
+				So(keys, ShouldHaveLength, 1)
+				So(keys[0].Hash, ShouldNotEqual, rotationKey)
+				So(keys[0].Matches(rotationKey), ShouldBeTrue)
+				So(keys[0].ExpiresAt, ShouldNotBeNil)
+				So(keys[0].ExpiresAt.Equal(expiresAt), ShouldBeTrue)
+			})
+
+			Convey("FindByFilter by ids", func() {
+				keys, err := port.ClientKey().FindByFilter(ctx, model.ClientKeyFilter{ClientIDs: []int{clientB.ID}})
+				So(err, ShouldBeNil)
+				So(keys, ShouldHaveLength, 1)
+
+				keys, err = port.ClientKey().FindByFilter(ctx, model.ClientKeyFilter{IDs: []int{keys[0].ID}})
+				So(err, ShouldBeNil)
+				So(keys, ShouldHaveLength, 1)
+				So(keys[0].ClientID, ShouldEqual, clientB.ID)
+			})
+
+			Convey("FindByFilter by labels", func() {
+				keys, err := port.ClientKey().FindByFilter(ctx, model.ClientKeyFilter{Labels: []string{"default"}})
+				So(err, ShouldBeNil)
+				So(keys, ShouldHaveLength, 2)
+			})
+
+			Convey("FindByFilter by prefix finds the client of a bearer key", func() {
+				keys, err := port.ClientKey().FindByFilter(ctx, model.ClientKeyFilter{Prefixes: []string{model.BearerKeyPrefix(defaultKey)}})
+				So(err, ShouldBeNil)
+				So(keys, ShouldHaveLength, 1)
+				So(keys[0].ClientID, ShouldEqual, clientA.ID)
+				So(keys[0].ExpiresAt, ShouldBeNil)
+			})
+
+			Convey("FindByFilter by an unknown prefix finds nothing", func() {
+				keys, err := port.ClientKey().FindByFilter(ctx, model.ClientKeyFilter{Prefixes: []string{model.BearerKeyPrefix(model.GenerateBearerKey())}})
+				So(err, ShouldBeNil)
+				So(keys, ShouldBeEmpty)
+			})
+
+			Convey("DeleteByFilter deletes only the matching keys", func() {
+				So(port.ClientKey().DeleteByFilter(ctx, model.ClientKeyFilter{ClientIDs: []int{clientA.ID}, Labels: []string{"default"}}), ShouldBeNil)
+
+				keys, err := port.ClientKey().FindByFilter(ctx, model.ClientKeyFilter{})
+				So(err, ShouldBeNil)
+				So(keys, ShouldHaveLength, 2)
+			})
+		})
+
+		Convey("Outbox", func() {
+			insertMessage := func(aggregateID string) {
+				message, err := model.NewOutboxMessage(model.UpsertClientMessage, model.ClientAggregate, aggregateID, []string{aggregateID})
+				So(err, ShouldBeNil)
+				message.AvailableAt, message.CreatedAt = now, now
+				So(port.Outbox().Insert(ctx, []model.OutboxMessageInput{message}), ShouldBeNil)
+			}
+			insertMessage("a")
+			insertMessage("a")
+			insertMessage("b")
+
+			Convey("FindPending returns the oldest unpublished message of each aggregate", func() {
+				messages, err := port.Outbox().FindPending(ctx, now, 10)
+				So(err, ShouldBeNil)
+				So(messages, ShouldHaveLength, 2)
+				So(messages[0].AggregateID, ShouldEqual, "a")
+				So(messages[0].Payload, ShouldEqual, `["a"]`)
+				So(messages[1].AggregateID, ShouldEqual, "b")
+				So(messages[0].ID, ShouldBeLessThan, messages[1].ID)
+			})
+
+			Convey("MarkPublished moves the aggregate to its next message", func() {
+				messages, err := port.Outbox().FindPending(ctx, now, 10)
+				So(err, ShouldBeNil)
+				So(port.Outbox().MarkPublished(ctx, []int{messages[0].ID}, now), ShouldBeNil)
+
+				next, err := port.Outbox().FindPending(ctx, now, 10)
+				So(err, ShouldBeNil)
+				So(next, ShouldHaveLength, 2)
+				So(next[0].AggregateID, ShouldEqual, "a")
+				So(next[0].ID, ShouldBeGreaterThan, messages[0].ID)
+				So(next[1].AggregateID, ShouldEqual, "b")
+			})
+
+			Convey("MarkFailed holds the aggregate until the message is available", func() {
+				messages, err := port.Outbox().FindPending(ctx, now, 10)
+				So(err, ShouldBeNil)
+				So(port.Outbox().MarkFailed(ctx, messages[0].ID, "channel closed", now.Add(time.Minute)), ShouldBeNil)
+
+				pending, err := port.Outbox().FindPending(ctx, now, 10)
+				So(err, ShouldBeNil)
+				So(pending, ShouldHaveLength, 1)
+				So(pending[0].AggregateID, ShouldEqual, "b")
+
+				pending, err = port.Outbox().FindPending(ctx, now.Add(time.Minute), 10)
+				So(err, ShouldBeNil)
+				So(pending, ShouldHaveLength, 2)
+				So(pending[0].Attempts, ShouldEqual, 1)
+				So(pending[0].LastError, ShouldEqual, "channel closed")
+			})
+
+			Convey("DeletePublished deletes only the messages published before the time", func() {
+				messages, err := port.Outbox().FindPending(ctx, now, 10)
+				So(err, ShouldBeNil)
+				So(port.Outbox().MarkPublished(ctx, []int{messages[0].ID}, now.Add(-time.Hour)), ShouldBeNil)
+				So(port.Outbox().MarkPublished(ctx, []int{messages[1].ID}, now), ShouldBeNil)
+				So(port.Outbox().DeletePublished(ctx, now.Add(-time.Minute)), ShouldBeNil)
+
+				pending, err := port.Outbox().FindPending(ctx, now, 10)
+				So(err, ShouldBeNil)
+				So(pending, ShouldHaveLength, 1)
+				So(pending[0].AggregateID, ShouldEqual, "a")
+			})
+
+			Convey("Messages written in a rolled back transaction are not stored", func() {
+				_, err := port.DoInTransaction(ctx, func(tx outbound_port.DatabasePort) (interface{}, error) {
+					message, err := model.NewOutboxMessage(model.UpsertClientMessage, model.ClientAggregate, "c", nil)
+					if err != nil {
+						return nil, err
+					}
+					message.AvailableAt = now
+					if err := tx.Outbox().Insert(ctx, []model.OutboxMessageInput{message}); err != nil {
+						return nil, err
+					}
+					return nil, errors.New("failed")
+				})
+				So(err, ShouldNotBeNil)
+
+				pending, err := port.Outbox().FindPending(ctx, now, 10)
+				So(err, ShouldBeNil)
+				So(pending, ShouldHaveLength, 2)
+			})
+		})
+
+		Convey("Ping", func() {
+			So(port.Ping(ctx), ShouldBeNil)
+
+			_, err := port.DoInTransaction(ctx, func(tx outbound_port.DatabasePort) (interface{}, error) {
+				return nil, tx.Ping(ctx)
+			})
+			So(err, ShouldBeNil)
+		})
+
+		Convey("DoInTransaction", func() {
+			input := model.ClientInput{Name: "Client C", CreatedAt: now, UpdatedAt: now}
+
+			Convey("Commits when the function succeeds", func() {
+				out, err := port.DoInTransaction(ctx, func(tx outbound_port.DatabasePort) (interface{}, error) {
+					return "done", tx.Client().Upsert(ctx, []model.ClientInput{input})
+				})
+				So(err, ShouldBeNil)
+				So(out, ShouldEqual, "done")
+				So(clientExists(ctx, port, "Client C"), ShouldBeTrue)
+			})
+
+			Convey("Rolls back and returns the error when the function fails", func() {
+				_, err := port.DoInTransaction(ctx, func(tx outbound_port.DatabasePort) (interface{}, error) {
+					if err := tx.Client().Upsert(ctx, []model.ClientInput{input}); err != nil {
+						return nil, err
+					}
+					return nil, errors.New("failed")
+				})
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "failed")
+				So(clientExists(ctx, port, "Client C"), ShouldBeFalse)
+			})
+
+			Convey("Rolls back and returns an error when the function panics", func() {
+				_, err := port.DoInTransaction(ctx, func(tx outbound_port.DatabasePort) (interface{}, error) {
+					if err := tx.Client().Upsert(ctx, []model.ClientInput{input}); err != nil {
+						return nil, err
+					}
+					panic("boom")
+				})
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "boom")
+				So(clientExists(ctx, port, "Client C"), ShouldBeFalse)
+			})
+
+			Convey("Nested transactions share the outer transaction", func() {
+				_, err := port.DoInTransaction(ctx, func(tx outbound_port.DatabasePort) (interface{}, error) {
+					_, err := tx.DoInTransaction(ctx, func(tx outbound_port.DatabasePort) (interface{}, error) {
+						return nil, tx.Client().Upsert(ctx, []model.ClientInput{input})
+					})
+					if err != nil {
+						return nil, err
+					}
+					return nil, errors.New("failed")
+				})
+				So(err, ShouldNotBeNil)
+				So(clientExists(ctx, port, "Client C"), ShouldBeFalse)
+			})
+		})
+	})
+}
+
+func upsertClient(ctx context.Context, port outbound_port.DatabasePort, name string, now time.Time) model.Client {
+	input := model.ClientInput{Name: name, CreatedAt: now, UpdatedAt: now}
+	So(port.Client().Upsert(ctx, []model.ClientInput{input}), ShouldBeNil)
+
+	clients, err := port.Client().FindByFilter(ctx, model.ClientFilter{Names: []string{name}}, false)
+	So(err, ShouldBeNil)
+	So(clients, ShouldHaveLength, 1)
+	return clients[0]
+}
+
+func upsertRole(ctx context.Context, port outbound_port.DatabasePort, clientID int, role model.Role, now time.Time) {
+	input := model.ClientRoleInput{ClientID: clientID, Role: role, CreatedAt: now, UpdatedAt: now}
+	So(port.ClientRole().Upsert(ctx, []model.ClientRoleInput{input}), ShouldBeNil)
+}
+
+// upsertKey stores a new key under label and returns the bearer key
+func upsertKey(ctx context.Context, port outbound_port.DatabasePort, clientID int, label string, expiresAt *time.Time, now time.Time) string {
+	bearerKey := model.GenerateBearerKey()
+	input := model.ClientKeyInput{ClientID: clientID, Label: label, ExpiresAt: expiresAt}
+	model.ClientKeyPrepare(&input, bearerKey)
+	input.CreatedAt, input.UpdatedAt = now, now
+	So(port.ClientKey().Upsert(ctx, []model.ClientKeyInput{input}), ShouldBeNil)
+	return bearerKey
+}
+
+func clientExists(ctx context.Context, port outbound_port.DatabasePort, name string) bool {
+	clients, err := port.Client().FindByFilter(ctx, model.ClientFilter{Names: []string{name}}, false)
+	So(err, ShouldBeNil)
+	return len(clients) > 0
+}
+
+func clientNames(clients []model.Client) []string {
+	names := []string{}
+	for _, client := range clients {
+		names = append(names, client.Name)
+	}
+	sort.Strings(names)
+	return names
+}
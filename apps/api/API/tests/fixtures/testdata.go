@@ -16,7 +16,6 @@ func (c *ClientTestData) ValidClientInput() model.ClientInput {
 	now := time.Now()
 	return model.ClientInput{
 		Name:      "Test Client",
-		BearerKey: "test-bearer-key-" + now.Format("20060102150405"),
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -31,9 +30,8 @@ func (c *ClientTestData) ValidClient() model.Client {
 
 func (c *ClientTestData) ValidClientFilter() model.ClientFilter {
 	return model.ClientFilter{
-		IDs:        []int{1},
-		Names:      []string{"Test Client"},
-		BearerKeys: []string{"test-bearer-key"},
+		IDs:   []int{1},
+		Names: []string{"Test Client"},
 	}
 }
 
@@ -45,7 +43,6 @@ func (c *ClientTestData) MultipleClients(count int) []model.Client {
 			ID: i + 1,
 			ClientInput: model.ClientInput{
 				Name:      "Client " + string(rune('A'+i)),
-				BearerKey: "key-" + string(rune('a'+i)),
 				CreatedAt: now,
 				UpdatedAt: now,
 			},
@@ -60,7 +57,6 @@ func (c *ClientTestData) MultipleClientInputs(count int) []model.ClientInput {
 	for i := 0; i < count; i++ {
 		inputs[i] = model.ClientInput{
 			Name:      "Client " + string(rune('A'+i)),
-			BearerKey: "key-" + string(rune('a'+i)) + "-" + now.Format("150405"),
 			CreatedAt: now,
 			UpdatedAt: now,
 		}
@@ -15,7 +15,7 @@ import (
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/testcontainers/testcontainers-go/wait"
 
-	postgres_outbound_adapter "prabogo/internal/adapter/outbound/postgres"
+	sql_outbound_adapter "prabogo/internal/adapter/outbound/sql"
 	"prabogo/internal/model"
 )
 
@@ -55,10 +55,21 @@ func TestClientIntegration(t *testing.T) {
 	_, err = db.Exec(`
 		CREATE TABLE IF NOT EXISTS clients (
 			id SERIAL PRIMARY KEY,
-			name VARCHAR(255) NOT NULL,
-			bearer_key VARCHAR(255) UNIQUE NOT NULL,
+			name VARCHAR(255) UNIQUE NOT NULL,
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS client_keys (
+			id SERIAL PRIMARY KEY,
+			client_id INTEGER NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+			label VARCHAR(100) NOT NULL,
+			prefix VARCHAR(16) NOT NULL,
+			salt VARCHAR(64) NOT NULL,
+			hash VARCHAR(64) NOT NULL,
+			expires_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+			UNIQUE (client_id, label)
 		)
 	`)
 	if err != nil {
@@ -66,85 +77,79 @@ func TestClientIntegration(t *testing.T) {
 	}
 
 	Convey("Test Client Integration with PostgreSQL", t, func() {
-		adapter := postgres_outbound_adapter.NewClientAdapter(db)
+		adapter := sql_outbound_adapter.NewClientAdapter(db, sql_outbound_adapter.DialectPostgres, 0)
+		keyAdapter := sql_outbound_adapter.NewClientKeyAdapter(db, sql_outbound_adapter.DialectPostgres, 0)
 
 		Convey("Full CRUD cycle", func() {
-			bearerKey := "integration-key-" + time.Now().Format("20060102150405.000")
+			name := "Integration Test Client " + time.Now().Format("20060102150405.000")
 			input := model.ClientInput{
-				Name:      "Integration Test Client",
-				BearerKey: bearerKey,
+				Name:      name,
 				CreatedAt: time.Now(),
 				UpdatedAt: time.Now(),
 			}
 
 			Convey("Upsert creates a new client", func() {
-				err := adapter.Upsert([]model.ClientInput{input})
+				err := adapter.Upsert(ctx, []model.ClientInput{input})
+				So(err, ShouldBeNil)
+
+				filter := model.ClientFilter{Names: []string{name}}
+				results, err := adapter.FindByFilter(ctx, filter, false)
 				So(err, ShouldBeNil)
+				So(len(results), ShouldEqual, 1)
+				So(results[0].Name, ShouldEqual, name)
+
+				Convey("Upsert on the same name keeps one client", func() {
+					err := adapter.Upsert(ctx, []model.ClientInput{input})
+					So(err, ShouldBeNil)
 
-				Convey("FindByFilter retrieves the client", func() {
-					filter := model.ClientFilter{BearerKeys: []string{bearerKey}}
-					results, err := adapter.FindByFilter(filter, false)
+					results, err := adapter.FindByFilter(ctx, filter, false)
 					So(err, ShouldBeNil)
 					So(len(results), ShouldEqual, 1)
-					So(results[0].Name, ShouldEqual, "Integration Test Client")
-					So(results[0].BearerKey, ShouldEqual, bearerKey)
 				})
 
-				Convey("IsExists returns true for existing client", func() {
-					exists, err := adapter.IsExists(bearerKey)
+				Convey("Keys are stored hashed and found by prefix", func() {
+					bearerKey := model.GenerateBearerKey()
+					keyInput := model.ClientKeyInput{ClientID: results[0].ID}
+					model.ClientKeyPrepare(&keyInput, bearerKey)
+					err := keyAdapter.Upsert(ctx, []model.ClientKeyInput{keyInput})
 					So(err, ShouldBeNil)
-					So(exists, ShouldBeTrue)
-				})
 
-				Convey("Upsert updates existing client", func() {
-					updatedInput := model.ClientInput{
-						Name:      "Updated Client Name",
-						BearerKey: bearerKey,
-						CreatedAt: input.CreatedAt,
-						UpdatedAt: time.Now(),
-					}
-					err := adapter.Upsert([]model.ClientInput{updatedInput})
+					var stored string
+					err = db.QueryRow(`SELECT hash FROM client_keys WHERE client_id = $1`, results[0].ID).Scan(&stored)
 					So(err, ShouldBeNil)
+					So(stored, ShouldNotEqual, bearerKey)
 
-					filter := model.ClientFilter{BearerKeys: []string{bearerKey}}
-					results, err := adapter.FindByFilter(filter, false)
+					keys, err := keyAdapter.FindByFilter(ctx, model.ClientKeyFilter{Prefixes: []string{model.BearerKeyPrefix(bearerKey)}})
 					So(err, ShouldBeNil)
-					So(len(results), ShouldEqual, 1)
-					So(results[0].Name, ShouldEqual, "Updated Client Name")
+					So(len(keys), ShouldEqual, 1)
+					So(keys[0].Matches(bearerKey), ShouldBeTrue)
 				})
 
 				Convey("DeleteByFilter removes the client", func() {
-					filter := model.ClientFilter{BearerKeys: []string{bearerKey}}
-					err := adapter.DeleteByFilter(filter)
+					err := adapter.DeleteByFilter(ctx, filter)
 					So(err, ShouldBeNil)
 
-					exists, err := adapter.IsExists(bearerKey)
+					results, err := adapter.FindByFilter(ctx, filter, false)
 					So(err, ShouldBeNil)
-					So(exists, ShouldBeFalse)
+					So(len(results), ShouldEqual, 0)
 				})
 			})
 		})
 
-		Convey("IsExists returns false for non-existent client", func() {
-			exists, err := adapter.IsExists("nonexistent-key-" + time.Now().Format("20060102150405"))
-			So(err, ShouldBeNil)
-			So(exists, ShouldBeFalse)
-		})
-
 		Convey("FindByFilter with multiple filters", func() {
 			now := time.Now()
 			clients := []model.ClientInput{
-				{Name: "Client A", BearerKey: "key-a-" + now.Format("150405.000"), CreatedAt: now, UpdatedAt: now},
-				{Name: "Client B", BearerKey: "key-b-" + now.Format("150405.000"), CreatedAt: now, UpdatedAt: now},
+				{Name: "Client A " + now.Format("150405.000"), CreatedAt: now, UpdatedAt: now},
+				{Name: "Client B " + now.Format("150405.000"), CreatedAt: now, UpdatedAt: now},
 			}
 
-			err := adapter.Upsert(clients)
+			err := adapter.Upsert(ctx, clients)
 			So(err, ShouldBeNil)
 
-			filter := model.ClientFilter{Names: []string{"Client A", "Client B"}}
-			results, err := adapter.FindByFilter(filter, false)
+			filter := model.ClientFilter{Names: []string{clients[0].Name, clients[1].Name}}
+			results, err := adapter.FindByFilter(ctx, filter, false)
 			So(err, ShouldBeNil)
-			So(len(results), ShouldBeGreaterThanOrEqualTo, 2)
+			So(len(results), ShouldEqual, 2)
 		})
 	})
 }
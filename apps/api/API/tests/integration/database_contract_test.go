@@ -0,0 +1,72 @@
+//go:build integration
+// +build integration
+
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/pressly/goose/v3"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	postgres_outbound_adapter "prabogo/internal/adapter/outbound/postgres"
+	_ "prabogo/internal/migration/postgres"
+	outbound_port "prabogo/internal/port/outbound"
+	"prabogo/tests/contract"
+)
+
+func TestPostgresDatabasePortContract(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+
+	pgContainer, err := postgres.Run(ctx,
+		"postgres:14-alpine",
+		postgres.WithDatabase("testdb"),
+		postgres.WithUsername("testuser"),
+		postgres.WithPassword("testpass"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(30*time.Second)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to start postgres container: %v", err)
+	}
+	defer pgContainer.Terminate(ctx)
+
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatalf("Failed to get connection string: %v", err)
+	}
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, nil)
+	if err != nil {
+		t.Fatalf("Failed to create migration provider: %v", err)
+	}
+	if _, err := provider.Up(ctx); err != nil {
+		t.Fatalf("Failed to run migrations: %v", err)
+	}
+
+	contract.DatabasePort(t, "postgres", func() outbound_port.DatabasePort {
+		_, err := db.Exec(`TRUNCATE outbox_messages, client_keys, client_roles, clients RESTART IDENTITY CASCADE`)
+		if err != nil {
+			t.Fatalf("Failed to truncate tables: %v", err)
+		}
+		return postgres_outbound_adapter.NewAdapter(db)
+	})
+}
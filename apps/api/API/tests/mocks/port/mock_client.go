@@ -5,8 +5,10 @@
 package mock_outbound_port
 
 import (
+	context "context"
 	model "prabogo/internal/model"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -34,62 +36,62 @@ func (m *MockClientDatabasePort) EXPECT() *MockClientDatabasePortMockRecorder {
 	return m.recorder
 }
 
+// CountByFilter mocks base method.
+func (m *MockClientDatabasePort) CountByFilter(ctx context.Context, filter model.ClientFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByFilter", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByFilter indicates an expected call of CountByFilter.
+func (mr *MockClientDatabasePortMockRecorder) CountByFilter(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByFilter", reflect.TypeOf((*MockClientDatabasePort)(nil).CountByFilter), ctx, filter)
+}
+
 // DeleteByFilter mocks base method.
-func (m *MockClientDatabasePort) DeleteByFilter(filter model.ClientFilter) error {
+func (m *MockClientDatabasePort) DeleteByFilter(ctx context.Context, filter model.ClientFilter) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteByFilter", filter)
+	ret := m.ctrl.Call(m, "DeleteByFilter", ctx, filter)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteByFilter indicates an expected call of DeleteByFilter.
-func (mr *MockClientDatabasePortMockRecorder) DeleteByFilter(filter interface{}) *gomock.Call {
+func (mr *MockClientDatabasePortMockRecorder) DeleteByFilter(ctx, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByFilter", reflect.TypeOf((*MockClientDatabasePort)(nil).DeleteByFilter), filter)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByFilter", reflect.TypeOf((*MockClientDatabasePort)(nil).DeleteByFilter), ctx, filter)
 }
 
 // FindByFilter mocks base method.
-func (m *MockClientDatabasePort) FindByFilter(filter model.ClientFilter, lock bool) ([]model.Client, error) {
+func (m *MockClientDatabasePort) FindByFilter(ctx context.Context, filter model.ClientFilter, lock bool) ([]model.Client, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindByFilter", filter, lock)
+	ret := m.ctrl.Call(m, "FindByFilter", ctx, filter, lock)
 	ret0, _ := ret[0].([]model.Client)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindByFilter indicates an expected call of FindByFilter.
-func (mr *MockClientDatabasePortMockRecorder) FindByFilter(filter, lock interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByFilter", reflect.TypeOf((*MockClientDatabasePort)(nil).FindByFilter), filter, lock)
-}
-
-// IsExists mocks base method.
-func (m *MockClientDatabasePort) IsExists(bearerKey string) (bool, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsExists", bearerKey)
-	ret0, _ := ret[0].(bool)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// IsExists indicates an expected call of IsExists.
-func (mr *MockClientDatabasePortMockRecorder) IsExists(bearerKey interface{}) *gomock.Call {
+func (mr *MockClientDatabasePortMockRecorder) FindByFilter(ctx, filter, lock interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsExists", reflect.TypeOf((*MockClientDatabasePort)(nil).IsExists), bearerKey)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByFilter", reflect.TypeOf((*MockClientDatabasePort)(nil).FindByFilter), ctx, filter, lock)
 }
 
 // Upsert mocks base method.
-func (m *MockClientDatabasePort) Upsert(datas []model.ClientInput) error {
+func (m *MockClientDatabasePort) Upsert(ctx context.Context, datas []model.ClientInput) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Upsert", datas)
+	ret := m.ctrl.Call(m, "Upsert", ctx, datas)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Upsert indicates an expected call of Upsert.
-func (mr *MockClientDatabasePortMockRecorder) Upsert(datas interface{}) *gomock.Call {
+func (mr *MockClientDatabasePortMockRecorder) Upsert(ctx, datas interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockClientDatabasePort)(nil).Upsert), datas)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockClientDatabasePort)(nil).Upsert), ctx, datas)
 }
 
 // MockClientMessagePort is a mock of ClientMessagePort interface.
@@ -116,17 +118,17 @@ func (m *MockClientMessagePort) EXPECT() *MockClientMessagePortMockRecorder {
 }
 
 // PublishUpsert mocks base method.
-func (m *MockClientMessagePort) PublishUpsert(datas []model.ClientInput) error {
+func (m *MockClientMessagePort) PublishUpsert(ctx context.Context, datas []model.ClientInput) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PublishUpsert", datas)
+	ret := m.ctrl.Call(m, "PublishUpsert", ctx, datas)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // PublishUpsert indicates an expected call of PublishUpsert.
-func (mr *MockClientMessagePortMockRecorder) PublishUpsert(datas interface{}) *gomock.Call {
+func (mr *MockClientMessagePortMockRecorder) PublishUpsert(ctx, datas interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishUpsert", reflect.TypeOf((*MockClientMessagePort)(nil).PublishUpsert), datas)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishUpsert", reflect.TypeOf((*MockClientMessagePort)(nil).PublishUpsert), ctx, datas)
 }
 
 // MockClientCachePort is a mock of ClientCachePort interface.
@@ -152,33 +154,90 @@ func (m *MockClientCachePort) EXPECT() *MockClientCachePortMockRecorder {
 	return m.recorder
 }
 
+// Delete mocks base method.
+func (m *MockClientCachePort) Delete(ctx context.Context, bearerKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, bearerKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockClientCachePortMockRecorder) Delete(ctx, bearerKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockClientCachePort)(nil).Delete), ctx, bearerKey)
+}
+
+// DeleteByClientIDs mocks base method.
+func (m *MockClientCachePort) DeleteByClientIDs(ctx context.Context, clientIDs []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByClientIDs", ctx, clientIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByClientIDs indicates an expected call of DeleteByClientIDs.
+func (mr *MockClientCachePortMockRecorder) DeleteByClientIDs(ctx, clientIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByClientIDs", reflect.TypeOf((*MockClientCachePort)(nil).DeleteByClientIDs), ctx, clientIDs)
+}
+
 // Get mocks base method.
-func (m *MockClientCachePort) Get(bearerKey string) (model.Client, error) {
+func (m *MockClientCachePort) Get(ctx context.Context, bearerKey string) (model.Client, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", bearerKey)
+	ret := m.ctrl.Call(m, "Get", ctx, bearerKey)
 	ret0, _ := ret[0].(model.Client)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockClientCachePortMockRecorder) Get(bearerKey interface{}) *gomock.Call {
+func (mr *MockClientCachePortMockRecorder) Get(ctx, bearerKey interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockClientCachePort)(nil).Get), bearerKey)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockClientCachePort)(nil).Get), ctx, bearerKey)
 }
 
 // Set mocks base method.
-func (m *MockClientCachePort) Set(data model.Client) error {
+func (m *MockClientCachePort) Set(ctx context.Context, bearerKey string, data model.Client, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Set", data)
+	ret := m.ctrl.Call(m, "Set", ctx, bearerKey, data, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Set indicates an expected call of Set.
-func (mr *MockClientCachePortMockRecorder) Set(data interface{}) *gomock.Call {
+func (mr *MockClientCachePortMockRecorder) Set(ctx, bearerKey, data, version interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockClientCachePort)(nil).Set), ctx, bearerKey, data, version)
+}
+
+// SetUnknown mocks base method.
+func (m *MockClientCachePort) SetUnknown(ctx context.Context, bearerKey string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUnknown", ctx, bearerKey, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUnknown indicates an expected call of SetUnknown.
+func (mr *MockClientCachePortMockRecorder) SetUnknown(ctx, bearerKey, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUnknown", reflect.TypeOf((*MockClientCachePort)(nil).SetUnknown), ctx, bearerKey, ttl)
+}
+
+// Version mocks base method.
+func (m *MockClientCachePort) Version(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Version", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Version indicates an expected call of Version.
+func (mr *MockClientCachePortMockRecorder) Version(ctx interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockClientCachePort)(nil).Set), data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Version", reflect.TypeOf((*MockClientCachePort)(nil).Version), ctx)
 }
 
 // MockClientWorkflowPort is a mock of ClientWorkflowPort interface.
@@ -205,15 +264,15 @@ func (m *MockClientWorkflowPort) EXPECT() *MockClientWorkflowPortMockRecorder {
 }
 
 // StartUpsert mocks base method.
-func (m *MockClientWorkflowPort) StartUpsert(data model.ClientInput) error {
+func (m *MockClientWorkflowPort) StartUpsert(ctx context.Context, data model.ClientInput) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "StartUpsert", data)
+	ret := m.ctrl.Call(m, "StartUpsert", ctx, data)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // StartUpsert indicates an expected call of StartUpsert.
-func (mr *MockClientWorkflowPortMockRecorder) StartUpsert(data interface{}) *gomock.Call {
+func (mr *MockClientWorkflowPortMockRecorder) StartUpsert(ctx, data interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartUpsert", reflect.TypeOf((*MockClientWorkflowPort)(nil).StartUpsert), data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartUpsert", reflect.TypeOf((*MockClientWorkflowPort)(nil).StartUpsert), ctx, data)
 }
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: client_key.go
+
+// Package mock_outbound_port is a generated GoMock package.
+package mock_outbound_port
+
+import (
+	context "context"
+	model "prabogo/internal/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockClientKeyDatabasePort is a mock of ClientKeyDatabasePort interface.
+type MockClientKeyDatabasePort struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientKeyDatabasePortMockRecorder
+}
+
+// MockClientKeyDatabasePortMockRecorder is the mock recorder for MockClientKeyDatabasePort.
+type MockClientKeyDatabasePortMockRecorder struct {
+	mock *MockClientKeyDatabasePort
+}
+
+// NewMockClientKeyDatabasePort creates a new mock instance.
+func NewMockClientKeyDatabasePort(ctrl *gomock.Controller) *MockClientKeyDatabasePort {
+	mock := &MockClientKeyDatabasePort{ctrl: ctrl}
+	mock.recorder = &MockClientKeyDatabasePortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClientKeyDatabasePort) EXPECT() *MockClientKeyDatabasePortMockRecorder {
+	return m.recorder
+}
+
+// DeleteByFilter mocks base method.
+func (m *MockClientKeyDatabasePort) DeleteByFilter(ctx context.Context, filter model.ClientKeyFilter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByFilter", ctx, filter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByFilter indicates an expected call of DeleteByFilter.
+func (mr *MockClientKeyDatabasePortMockRecorder) DeleteByFilter(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByFilter", reflect.TypeOf((*MockClientKeyDatabasePort)(nil).DeleteByFilter), ctx, filter)
+}
+
+// FindByFilter mocks base method.
+func (m *MockClientKeyDatabasePort) FindByFilter(ctx context.Context, filter model.ClientKeyFilter) ([]model.ClientKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByFilter", ctx, filter)
+	ret0, _ := ret[0].([]model.ClientKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByFilter indicates an expected call of FindByFilter.
+func (mr *MockClientKeyDatabasePortMockRecorder) FindByFilter(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByFilter", reflect.TypeOf((*MockClientKeyDatabasePort)(nil).FindByFilter), ctx, filter)
+}
+
+// Upsert mocks base method.
+func (m *MockClientKeyDatabasePort) Upsert(ctx context.Context, datas []model.ClientKeyInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, datas)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockClientKeyDatabasePortMockRecorder) Upsert(ctx, datas interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockClientKeyDatabasePort)(nil).Upsert), ctx, datas)
+}
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: client_role.go
+
+// Package mock_outbound_port is a generated GoMock package.
+package mock_outbound_port
+
+import (
+	context "context"
+	model "prabogo/internal/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockClientRoleDatabasePort is a mock of ClientRoleDatabasePort interface.
+type MockClientRoleDatabasePort struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientRoleDatabasePortMockRecorder
+}
+
+// MockClientRoleDatabasePortMockRecorder is the mock recorder for MockClientRoleDatabasePort.
+type MockClientRoleDatabasePortMockRecorder struct {
+	mock *MockClientRoleDatabasePort
+}
+
+// NewMockClientRoleDatabasePort creates a new mock instance.
+func NewMockClientRoleDatabasePort(ctrl *gomock.Controller) *MockClientRoleDatabasePort {
+	mock := &MockClientRoleDatabasePort{ctrl: ctrl}
+	mock.recorder = &MockClientRoleDatabasePortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClientRoleDatabasePort) EXPECT() *MockClientRoleDatabasePortMockRecorder {
+	return m.recorder
+}
+
+// DeleteByFilter mocks base method.
+func (m *MockClientRoleDatabasePort) DeleteByFilter(ctx context.Context, filter model.ClientRoleFilter) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByFilter", ctx, filter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByFilter indicates an expected call of DeleteByFilter.
+func (mr *MockClientRoleDatabasePortMockRecorder) DeleteByFilter(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByFilter", reflect.TypeOf((*MockClientRoleDatabasePort)(nil).DeleteByFilter), ctx, filter)
+}
+
+// FindByFilter mocks base method.
+func (m *MockClientRoleDatabasePort) FindByFilter(ctx context.Context, filter model.ClientRoleFilter) ([]model.ClientRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByFilter", ctx, filter)
+	ret0, _ := ret[0].([]model.ClientRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByFilter indicates an expected call of FindByFilter.
+func (mr *MockClientRoleDatabasePortMockRecorder) FindByFilter(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByFilter", reflect.TypeOf((*MockClientRoleDatabasePort)(nil).FindByFilter), ctx, filter)
+}
+
+// Upsert mocks base method.
+func (m *MockClientRoleDatabasePort) Upsert(ctx context.Context, datas []model.ClientRoleInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, datas)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockClientRoleDatabasePortMockRecorder) Upsert(ctx, datas interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockClientRoleDatabasePort)(nil).Upsert), ctx, datas)
+}
@@ -0,0 +1,145 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: outbox.go
+
+// Package mock_outbound_port is a generated GoMock package.
+package mock_outbound_port
+
+import (
+	context "context"
+	model "prabogo/internal/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOutboxDatabasePort is a mock of OutboxDatabasePort interface.
+type MockOutboxDatabasePort struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxDatabasePortMockRecorder
+}
+
+// MockOutboxDatabasePortMockRecorder is the mock recorder for MockOutboxDatabasePort.
+type MockOutboxDatabasePortMockRecorder struct {
+	mock *MockOutboxDatabasePort
+}
+
+// NewMockOutboxDatabasePort creates a new mock instance.
+func NewMockOutboxDatabasePort(ctrl *gomock.Controller) *MockOutboxDatabasePort {
+	mock := &MockOutboxDatabasePort{ctrl: ctrl}
+	mock.recorder = &MockOutboxDatabasePortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxDatabasePort) EXPECT() *MockOutboxDatabasePortMockRecorder {
+	return m.recorder
+}
+
+// DeletePublished mocks base method.
+func (m *MockOutboxDatabasePort) DeletePublished(ctx context.Context, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePublished", ctx, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePublished indicates an expected call of DeletePublished.
+func (mr *MockOutboxDatabasePortMockRecorder) DeletePublished(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePublished", reflect.TypeOf((*MockOutboxDatabasePort)(nil).DeletePublished), ctx, before)
+}
+
+// FindPending mocks base method.
+func (m *MockOutboxDatabasePort) FindPending(ctx context.Context, now time.Time, limit int) ([]model.OutboxMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPending", ctx, now, limit)
+	ret0, _ := ret[0].([]model.OutboxMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPending indicates an expected call of FindPending.
+func (mr *MockOutboxDatabasePortMockRecorder) FindPending(ctx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPending", reflect.TypeOf((*MockOutboxDatabasePort)(nil).FindPending), ctx, now, limit)
+}
+
+// Insert mocks base method.
+func (m *MockOutboxDatabasePort) Insert(ctx context.Context, datas []model.OutboxMessageInput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, datas)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockOutboxDatabasePortMockRecorder) Insert(ctx, datas interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockOutboxDatabasePort)(nil).Insert), ctx, datas)
+}
+
+// MarkFailed mocks base method.
+func (m *MockOutboxDatabasePort) MarkFailed(ctx context.Context, id int, reason string, availableAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", ctx, id, reason, availableAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockOutboxDatabasePortMockRecorder) MarkFailed(ctx, id, reason, availableAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockOutboxDatabasePort)(nil).MarkFailed), ctx, id, reason, availableAt)
+}
+
+// MarkPublished mocks base method.
+func (m *MockOutboxDatabasePort) MarkPublished(ctx context.Context, ids []int, publishedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPublished", ctx, ids, publishedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPublished indicates an expected call of MarkPublished.
+func (mr *MockOutboxDatabasePortMockRecorder) MarkPublished(ctx, ids, publishedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPublished", reflect.TypeOf((*MockOutboxDatabasePort)(nil).MarkPublished), ctx, ids, publishedAt)
+}
+
+// MockOutboxMessagePort is a mock of OutboxMessagePort interface.
+type MockOutboxMessagePort struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxMessagePortMockRecorder
+}
+
+// MockOutboxMessagePortMockRecorder is the mock recorder for MockOutboxMessagePort.
+type MockOutboxMessagePortMockRecorder struct {
+	mock *MockOutboxMessagePort
+}
+
+// NewMockOutboxMessagePort creates a new mock instance.
+func NewMockOutboxMessagePort(ctrl *gomock.Controller) *MockOutboxMessagePort {
+	mock := &MockOutboxMessagePort{ctrl: ctrl}
+	mock.recorder = &MockOutboxMessagePortMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxMessagePort) EXPECT() *MockOutboxMessagePortMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockOutboxMessagePort) Publish(ctx context.Context, message model.OutboxMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockOutboxMessagePortMockRecorder) Publish(ctx, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockOutboxMessagePort)(nil).Publish), ctx, message)
+}
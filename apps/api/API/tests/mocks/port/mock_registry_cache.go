@@ -5,7 +5,9 @@
 package mock_outbound_port
 
 import (
+	context "context"
 	outbound_port "prabogo/internal/port/outbound"
+	ratelimit "prabogo/utils/ratelimit"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -47,3 +49,31 @@ func (mr *MockCachePortMockRecorder) Client() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Client", reflect.TypeOf((*MockCachePort)(nil).Client))
 }
+
+// Ping mocks base method.
+func (m *MockCachePort) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockCachePortMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockCachePort)(nil).Ping), ctx)
+}
+
+// RateLimit mocks base method.
+func (m *MockCachePort) RateLimit() ratelimit.Store {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RateLimit")
+	ret0, _ := ret[0].(ratelimit.Store)
+	return ret0
+}
+
+// RateLimit indicates an expected call of RateLimit.
+func (mr *MockCachePortMockRecorder) RateLimit() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RateLimit", reflect.TypeOf((*MockCachePort)(nil).RateLimit))
+}
@@ -5,6 +5,7 @@
 package mock_outbound_port
 
 import (
+	context "context"
 	outbound_port "prabogo/internal/port/outbound"
 	reflect "reflect"
 
@@ -47,3 +48,31 @@ func (mr *MockMessagePortMockRecorder) Client() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Client", reflect.TypeOf((*MockMessagePort)(nil).Client))
 }
+
+// Outbox mocks base method.
+func (m *MockMessagePort) Outbox() outbound_port.OutboxMessagePort {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Outbox")
+	ret0, _ := ret[0].(outbound_port.OutboxMessagePort)
+	return ret0
+}
+
+// Outbox indicates an expected call of Outbox.
+func (mr *MockMessagePortMockRecorder) Outbox() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Outbox", reflect.TypeOf((*MockMessagePort)(nil).Outbox))
+}
+
+// Ping mocks base method.
+func (m *MockMessagePort) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockMessagePortMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockMessagePort)(nil).Ping), ctx)
+}
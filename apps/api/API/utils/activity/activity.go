@@ -14,6 +14,7 @@ const (
 	ClientID
 	Payload
 	Result
+	Roles
 )
 
 func NewContext(action string) context.Context {
@@ -22,6 +23,10 @@ func NewContext(action string) context.Context {
 	return context.WithValue(ctx, Action, action)
 }
 
+func WithTransactionID(ctx context.Context, trxID string) context.Context {
+	return context.WithValue(ctx, TransactionID, trxID)
+}
+
 func GetTransactionID(ctx context.Context) (string, bool) {
 	trxID, ok := ctx.Value(TransactionID).(string)
 	return trxID, ok
@@ -45,6 +50,15 @@ func GetClientID(ctx context.Context) (string, bool) {
 	return clientID, ok
 }
 
+func WithRoles(ctx context.Context, roles []string) context.Context {
+	return context.WithValue(ctx, Roles, roles)
+}
+
+func GetRoles(ctx context.Context) ([]string, bool) {
+	roles, ok := ctx.Value(Roles).([]string)
+	return roles, ok
+}
+
 func WithPayload(ctx context.Context, payload interface{}) context.Context {
 	return context.WithValue(ctx, Payload, payload)
 }
@@ -76,6 +90,10 @@ func GetFields(ctx context.Context) map[string]interface{} {
 		fields["client_id"] = clientID
 	}
 
+	if roles, ok := GetRoles(ctx); ok {
+		fields["roles"] = roles
+	}
+
 	fields["payload"] = GetPayload(ctx)
 	fields["result"] = GetResult(ctx)
 
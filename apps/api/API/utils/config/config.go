@@ -0,0 +1,283 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"prabogo/utils/validate"
+)
+
+// Fields are read from the struct tags:
+//
+//	env       the variable holding the value, NAME_FILE may name a file holding it instead
+//	default   the value used when the variable is not set
+//	secret       "true" redacts the value when printed
+//	validate     the rules of utils/validate, reported with the variable name
+//	description  what the variable sets, listed by Variables
+//
+// Supported types are string, bool, ints, floats, time.Duration and []string, which is
+// separated by commas or spaces. Nested and embedded structs are read too.
+const (
+	envTag         = "env"
+	defaultTag     = "default"
+	secretTag      = "secret"
+	descriptionTag = "description"
+	fileSuffix     = "_FILE"
+	redacted       = "******"
+)
+
+// Extended is implemented by targets reading variables the tags cannot describe, such as
+// a family sharing a prefix, or checking rules across fields. Load calls LoadExtended with
+// the environment once the tagged fields are set, Print calls PrintExtended after them.
+type Extended interface {
+	LoadExtended(environ []string) error
+	PrintExtended(w io.Writer) error
+}
+
+// Variable describes a variable read into a target
+type Variable struct {
+	Name        string
+	Default     string
+	Description string
+	Secret      bool
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// field is a tagged field of the target
+type field struct {
+	name   string
+	path   string
+	secret bool
+	value  reflect.Value
+	tag    reflect.StructTag
+}
+
+// Load fills target, a pointer to a struct, from the environment. Every invalid or
+// missing value is reported in the returned error, the valid ones are still set.
+func Load(target any) error {
+	fields, err := fieldsOf(target)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	names := make(map[string]string, len(fields))
+	for _, f := range fields {
+		names[f.path] = f.name
+
+		value, ok, err := lookup(f.name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !ok {
+			value, ok = f.tag.Lookup(defaultTag)
+		}
+		if !ok {
+			continue
+		}
+
+		if err := set(f.value, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.name, err))
+		}
+	}
+
+	var fieldErrors validate.Errors
+	if errors.As(validate.Struct(target), &fieldErrors) {
+		for _, fieldError := range fieldErrors {
+			name := names[fieldError.Field]
+			if name == "" {
+				name = fieldError.Field
+			}
+			errs = append(errs, fmt.Errorf("%s %s", name, fieldError.Message))
+		}
+	}
+
+	if extended, ok := target.(Extended); ok {
+		errs = append(errs, extended.LoadExtended(os.Environ()))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Print writes the variables of target with their values, secrets are redacted
+func Print(w io.Writer, target any) error {
+	fields, err := fieldsOf(target)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range fields {
+		value := format(f.value)
+		if f.secret && value != "" {
+			value = redacted
+		}
+		if _, err := fmt.Fprintf(w, "%s=%s\n", f.name, value); err != nil {
+			return err
+		}
+	}
+
+	if extended, ok := target.(Extended); ok {
+		return extended.PrintExtended(w)
+	}
+	return nil
+}
+
+// Variables returns the variables of target, a pointer to a struct, in field order
+func Variables(target any) ([]Variable, error) {
+	fields, err := fieldsOf(target)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := make([]Variable, len(fields))
+	for i, f := range fields {
+		variables[i] = Variable{
+			Name:        f.name,
+			Default:     f.tag.Get(defaultTag),
+			Description: f.tag.Get(descriptionTag),
+			Secret:      f.secret,
+		}
+	}
+	return variables, nil
+}
+
+// lookup returns the value of the variable name or of the file named by NAME_FILE
+func lookup(name string) (string, bool, error) {
+	value, ok := os.LookupEnv(name)
+	file, fileOK := os.LookupEnv(name + fileSuffix)
+	if !fileOK || file == "" {
+		return value, ok && value != "", nil
+	}
+	if ok && value != "" {
+		return "", false, fmt.Errorf("%s and %s%s are both set", name, name, fileSuffix)
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", false, fmt.Errorf("%s%s: %w", name, fileSuffix, err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), true, nil
+}
+
+func fieldsOf(target any) ([]field, error) {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config: %T is not a pointer to a struct", target)
+	}
+
+	var fields []field
+	walk(value.Elem(), "", &fields)
+	return fields, nil
+}
+
+// walk collects the tagged fields, their path matches the field paths of utils/validate
+func walk(value reflect.Value, path string, fields *[]field) {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		structField := valueType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		fieldPath := path
+		if !structField.Anonymous {
+			fieldPath = structField.Name
+			if path != "" {
+				fieldPath = path + "." + structField.Name
+			}
+		}
+
+		if name, ok := structField.Tag.Lookup(envTag); ok {
+			*fields = append(*fields, field{
+				name:   name,
+				path:   fieldPath,
+				secret: structField.Tag.Get(secretTag) == "true",
+				value:  value.Field(i),
+				tag:    structField.Tag,
+			})
+			continue
+		}
+
+		if structField.Type.Kind() == reflect.Struct && structField.Type != durationType {
+			walk(value.Field(i), fieldPath, fields)
+		}
+	}
+}
+
+func set(value reflect.Value, text string) error {
+	if value.Type() == durationType {
+		duration, err := time.ParseDuration(text)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", text)
+		}
+		value.SetInt(int64(duration))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(text)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			return fmt.Errorf("invalid bool %q", text)
+		}
+		value.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(text, 10, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", text)
+		}
+		value.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(text, 10, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", text)
+		}
+		value.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(text, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", text)
+		}
+		value.SetFloat(parsed)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() != reflect.String {
+			panic(fmt.Sprintf("config: unsupported type %s", value.Type()))
+		}
+		value.Set(reflect.ValueOf(SplitList(text)))
+	default:
+		panic(fmt.Sprintf("config: unsupported type %s", value.Type()))
+	}
+	return nil
+}
+
+func format(value reflect.Value) string {
+	switch {
+	case value.Type() == durationType:
+		return time.Duration(value.Int()).String()
+	case value.Kind() == reflect.Slice:
+		items := make([]string, value.Len())
+		for i := range items {
+			items[i] = value.Index(i).String()
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
+
+// SplitList splits a list separated by commas or spaces
+func SplitList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
@@ -0,0 +1,102 @@
+package config_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/utils/config"
+)
+
+type Server struct {
+	Host     string `env:"TEST_HOST" validate:"required" description:"server host"`
+	Port     int    `env:"TEST_PORT" default:"8000" validate:"min=1"`
+	Password string `env:"TEST_PASSWORD" secret:"true"`
+}
+
+type settings struct {
+	Server
+	Timeout time.Duration `env:"TEST_TIMEOUT" default:"5s"`
+	Debug   bool          `env:"TEST_DEBUG"`
+	Scopes  []string      `env:"TEST_SCOPES"`
+}
+
+func TestLoad(t *testing.T) {
+	Convey("Test Config Load", t, func() {
+		var target settings
+
+		Convey("Reads variables and defaults", func() {
+			os.Setenv("TEST_HOST", "db.local")
+			defer os.Unsetenv("TEST_HOST")
+			os.Setenv("TEST_DEBUG", "true")
+			defer os.Unsetenv("TEST_DEBUG")
+			os.Setenv("TEST_SCOPES", "read, write")
+			defer os.Unsetenv("TEST_SCOPES")
+
+			So(config.Load(&target), ShouldBeNil)
+			So(target.Host, ShouldEqual, "db.local")
+			So(target.Port, ShouldEqual, 8000)
+			So(target.Timeout, ShouldEqual, 5*time.Second)
+			So(target.Debug, ShouldBeTrue)
+			So(target.Scopes, ShouldResemble, []string{"read", "write"})
+		})
+
+		Convey("Reads a secret file", func() {
+			file := filepath.Join(t.TempDir(), "password")
+			So(os.WriteFile(file, []byte("s3cret\n"), 0o600), ShouldBeNil)
+			os.Setenv("TEST_HOST", "db.local")
+			defer os.Unsetenv("TEST_HOST")
+			os.Setenv("TEST_PASSWORD_FILE", file)
+			defer os.Unsetenv("TEST_PASSWORD_FILE")
+
+			So(config.Load(&target), ShouldBeNil)
+			So(target.Password, ShouldEqual, "s3cret")
+		})
+
+		Convey("Reports every invalid variable", func() {
+			os.Setenv("TEST_PORT", "0")
+			defer os.Unsetenv("TEST_PORT")
+			os.Setenv("TEST_TIMEOUT", "soon")
+			defer os.Unsetenv("TEST_TIMEOUT")
+			os.Setenv("TEST_PASSWORD", "s3cret")
+			defer os.Unsetenv("TEST_PASSWORD")
+			os.Setenv("TEST_PASSWORD_FILE", "/run/secrets/password")
+			defer os.Unsetenv("TEST_PASSWORD_FILE")
+
+			err := config.Load(&target)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "TEST_PASSWORD and TEST_PASSWORD_FILE are both set")
+			So(err.Error(), ShouldContainSubstring, `TEST_TIMEOUT: invalid duration "soon"`)
+			So(err.Error(), ShouldContainSubstring, "TEST_HOST is required")
+			So(err.Error(), ShouldContainSubstring, "TEST_PORT must be at least 1")
+		})
+
+		Convey("Prints with secrets redacted", func() {
+			os.Setenv("TEST_HOST", "db.local")
+			defer os.Unsetenv("TEST_HOST")
+			os.Setenv("TEST_PASSWORD", "s3cret")
+			defer os.Unsetenv("TEST_PASSWORD")
+			So(config.Load(&target), ShouldBeNil)
+
+			var out bytes.Buffer
+			So(config.Print(&out, &target), ShouldBeNil)
+			So(out.String(), ShouldContainSubstring, "TEST_HOST=db.local\n")
+			So(out.String(), ShouldContainSubstring, "TEST_PASSWORD=******\n")
+			So(out.String(), ShouldContainSubstring, "TEST_TIMEOUT=5s\n")
+			So(out.String(), ShouldNotContainSubstring, "s3cret")
+		})
+
+		Convey("Lists the variables", func() {
+			variables, err := config.Variables(&target)
+			So(err, ShouldBeNil)
+			So(variables, ShouldHaveLength, 6)
+			So(variables[0], ShouldResemble, config.Variable{Name: "TEST_HOST", Description: "server host"})
+			So(variables[1], ShouldResemble, config.Variable{Name: "TEST_PORT", Default: "8000"})
+			So(variables[2].Secret, ShouldBeTrue)
+		})
+	})
+}
@@ -3,30 +3,90 @@ package database
 import (
 	"context"
 	"database/sql"
-	"os"
+	"fmt"
 
 	"github.com/pressly/goose/v3"
-
-	"prabogo/utils"
-	"prabogo/utils/log"
 )
 
-func InitDatabase(ctx context.Context, outboundDatabaseDriver string) *sql.DB {
-	db, err := sql.Open(outboundDatabaseDriver, utils.GetDatabaseString())
+// Config is the connection of a database server, the sqlite driver only sets Name
+type Config struct {
+	Host     string `env:"DATABASE_HOST" validate:"required" description:"server host"`
+	Port     string `env:"DATABASE_PORT" default:"5432" description:"server port"`
+	Username string `env:"DATABASE_USERNAME" validate:"required" description:"user name"`
+	Password string `env:"DATABASE_PASSWORD" secret:"true" description:"user password"`
+	Name     string `env:"DATABASE_NAME" validate:"required" description:"database name"`
+	SSLMode  string `env:"DATABASE_SSLMODE" default:"require" description:"lib/pq sslmode"`
+}
+
+// DataSource returns the connection string of driver
+func (c Config) DataSource(driver string) string {
+	if driver == "sqlite" {
+		// an in-memory database is named ":memory:", foreign keys are enforced
+		name := c.Name
+		if name == "" {
+			name = "prabogo.db"
+		}
+		return fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)", name)
+	}
+
+	return fmt.Sprintf("%s://%s:%s@%s:%s/%s?sslmode=%s&connect_timeout=5",
+		driver, c.Username, c.Password, c.Host, c.Port, c.Name, c.SSLMode)
+}
+
+// migrationDir is the directory of the SQL migrations of driver
+func migrationDir(driver string) string {
+	return fmt.Sprintf("./internal/migration/%s", driver)
+}
+
+// migrations holds the Go migrations of drivers that do not use the global goose registry
+var migrations = map[string][]*goose.Migration{}
+
+// AddMigration registers a migration for a driver other than postgres, whose
+// migrations live in the global goose registry
+func AddMigration(driver string, migration *goose.Migration) {
+	migrations[driver] = append(migrations[driver], migration)
+}
+
+// InitDatabase opens the database of a driver, checks the connection and applies the
+// pending migrations
+func InitDatabase(ctx context.Context, outboundDatabaseDriver string, cfg Config) (*sql.DB, error) {
+	db, err := sql.Open(outboundDatabaseDriver, cfg.DataSource(outboundDatabaseDriver))
 	if err != nil {
-		log.WithContext(ctx).Fatalf("failed to open database: %+v", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if outboundDatabaseDriver == "sqlite" {
+		// SQLite has a single writer, and an in-memory database lives in one connection
+		db.SetMaxOpenConns(1)
 	}
 
-	if err := db.Ping(); err != nil {
-		log.WithContext(ctx).Fatalf("failed to connect database: %+v", err)
-		os.Exit(1)
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect database: %w", err)
 	}
 
-	if err := goose.Up(db, utils.GetMigrationDir()); err != nil {
-		log.WithContext(ctx).Fatalf("failed to running migration: %+v", err)
-		os.Exit(1)
+	if err := Migrate(ctx, db, outboundDatabaseDriver); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to running migration: %w", err)
 	}
 
-	return db
+	return db, nil
+}
+
+// Migrate applies the pending migrations of a driver
+func Migrate(ctx context.Context, db *sql.DB, outboundDatabaseDriver string) error {
+	switch outboundDatabaseDriver {
+	case "sqlite":
+		provider, err := goose.NewProvider(goose.DialectSQLite3, db, nil,
+			goose.WithGoMigrations(migrations[outboundDatabaseDriver]...),
+			goose.WithDisableGlobalRegistry(true),
+		)
+		if err != nil {
+			return err
+		}
+		_, err = provider.Up(ctx)
+		return err
+	default:
+		return goose.Up(db, migrationDir(outboundDatabaseDriver))
+	}
 }
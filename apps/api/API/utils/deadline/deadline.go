@@ -0,0 +1,17 @@
+package deadline
+
+import (
+	"context"
+	"time"
+)
+
+// WithTimeout bounds ctx by timeout, 0 leaves it unbounded. The timeouts are configured by
+// the caller, e.g. DATABASE_TIMEOUT in the settings of the database drivers. An earlier
+// deadline already set on ctx is kept, and the returned cancel must always be called.
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,180 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// FirebaseCertsURL is the public x509 certificate endpoint for Firebase ID tokens
+	FirebaseCertsURL     = "https://www.googleapis.com/robot/v1/metadata/x509/securetoken@system.gserviceaccount.com"
+	firebaseIssuerPrefix = "https://securetoken.google.com/"
+	firebaseMaxUIDLength = 128
+)
+
+// firebaseReservedClaims are the standard claims excluded from custom claims
+var firebaseReservedClaims = map[string]struct{}{
+	"aud":            {},
+	"auth_time":      {},
+	"email":          {},
+	"email_verified": {},
+	"exp":            {},
+	"firebase":       {},
+	"iat":            {},
+	"iss":            {},
+	"name":           {},
+	"nbf":            {},
+	"phone_number":   {},
+	"picture":        {},
+	"sub":            {},
+	"user_id":        {},
+}
+
+// FirebaseToken represents a verified Firebase ID token
+type FirebaseToken struct {
+	UID           string
+	Email         string
+	EmailVerified bool
+	AuthTime      time.Time
+	Claims        map[string]interface{}
+}
+
+// X509Client handles fetching x509 certificates published as a kid to PEM map
+type X509Client struct {
+	certsURL string
+	client   *http.Client
+}
+
+// NewX509Client creates a new x509 certificate client with custom URL
+func NewX509Client(certsURL string) *X509Client {
+	return &X509Client{
+		certsURL: certsURL,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// FetchKeys fetches the certificates from the URL and returns their RSA public keys by kid,
+// it implements KeyFetcher
+func (c *X509Client) FetchKeys(ctx context.Context) (map[string]PublicKey, time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.certsURL, nil)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to fetch certificates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, time.Time{}, fmt.Errorf("certificate endpoint returned status %d", resp.StatusCode)
+	}
+
+	var certs map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&certs); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to decode certificates: %w", err)
+	}
+
+	keys := make(map[string]PublicKey, len(certs))
+	for kid, certPEM := range certs {
+		key, err := parseRSACertificate(certPEM)
+		if err != nil {
+			return nil, time.Time{}, fmt.Errorf("failed to parse certificate %s: %w", kid, err)
+		}
+		keys[kid] = PublicKey{Key: key, Algorithm: jwt.SigningMethodRS256.Alg()}
+	}
+
+	return keys, cacheExpiry(resp.Header), nil
+}
+
+func parseRSACertificate(certPEM string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		return nil, fmt.Errorf("invalid PEM block")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("certificate does not contain an RSA public key")
+	}
+
+	return key, nil
+}
+
+// ValidateFirebaseIDToken validates a Firebase ID token against the certificates at certsURL
+// and checks the securetoken issuer, the project audience, the subject and auth_time
+func ValidateFirebaseIDToken(tokenString, projectID, certsURL string) (*FirebaseToken, error) {
+	if projectID == "" {
+		return nil, fmt.Errorf("firebase project id is empty")
+	}
+	if certsURL == "" {
+		certsURL = FirebaseCertsURL
+	}
+
+	keySet := X509KeySet(certsURL)
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer(firebaseIssuerPrefix+projectID),
+		jwt.WithAudience(projectID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+
+	token, err := parser.Parse(tokenString, keyFunc(keySet))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse/validate token: %w", err)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse claims")
+	}
+
+	uid, _ := claims["sub"].(string)
+	if uid == "" {
+		return nil, fmt.Errorf("token has an empty subject")
+	}
+	if len(uid) > firebaseMaxUIDLength {
+		return nil, fmt.Errorf("token subject is longer than %d characters", firebaseMaxUIDLength)
+	}
+
+	authTime, ok := claims["auth_time"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("token does not contain auth_time claim")
+	}
+	authTimeValue := time.Unix(int64(authTime), 0)
+	if time.Now().Before(authTimeValue) {
+		return nil, fmt.Errorf("token auth_time %s is in the future", authTimeValue.Format(time.RFC3339))
+	}
+
+	result := &FirebaseToken{
+		UID:      uid,
+		AuthTime: authTimeValue,
+		Claims:   map[string]interface{}{},
+	}
+	result.Email, _ = claims["email"].(string)
+	result.EmailVerified, _ = claims["email_verified"].(bool)
+	for name, value := range claims {
+		if _, reserved := firebaseReservedClaims[name]; !reserved {
+			result.Claims[name] = value
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,217 @@
+package jwt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"prabogo/utils/log"
+)
+
+const (
+	defaultKeySetTTL                = time.Hour
+	defaultKeySetMinRefreshInterval = time.Minute
+	defaultKeySetMaxStale           = 24 * time.Hour
+	keySetRefreshAhead              = time.Minute
+	keySetFetchTimeout              = 30 * time.Second
+)
+
+// keySets holds the long-lived key sets keyed by source and URL
+var keySets sync.Map
+
+// KeyFetcher fetches public keys by kid and returns when they expire.
+// A zero expiry means the source did not say and the default TTL applies.
+type KeyFetcher func(ctx context.Context) (map[string]PublicKey, time.Time, error)
+
+// KeySetOptions configures a KeySet, zero values fall back to the defaults
+type KeySetOptions struct {
+	// DefaultTTL is used when the source sends no Cache-Control or Expires header
+	DefaultTTL time.Duration
+	// MinRefreshInterval rate-limits refetches on unknown kid and failed refreshes
+	MinRefreshInterval time.Duration
+	// MaxStale is how long an expired key set is still served while the source is down
+	MaxStale time.Duration
+}
+
+// KeySet caches public keys fetched from a remote endpoint
+type KeySet struct {
+	fetch   KeyFetcher
+	options KeySetOptions
+
+	fetchMu     sync.Mutex
+	mu          sync.RWMutex
+	keys        map[string]PublicKey
+	expiresAt   time.Time
+	lastAttempt time.Time
+	refreshing  bool
+}
+
+// NewKeySet creates a key set backed by the given fetcher
+func NewKeySet(fetch KeyFetcher, options KeySetOptions) *KeySet {
+	if options.DefaultTTL <= 0 {
+		options.DefaultTTL = defaultKeySetTTL
+	}
+	if options.MinRefreshInterval <= 0 {
+		options.MinRefreshInterval = defaultKeySetMinRefreshInterval
+	}
+	if options.MaxStale <= 0 {
+		options.MaxStale = defaultKeySetMaxStale
+	}
+
+	return &KeySet{
+		fetch:   fetch,
+		options: options,
+	}
+}
+
+// JWKSKeySet returns the shared key set for a JWKS URL
+func JWKSKeySet(jwksURL string) *KeySet {
+	return loadKeySet("jwks:"+jwksURL, func() *KeySet {
+		return NewKeySet(NewJWKSClient(jwksURL).FetchKeys, KeySetOptions{})
+	})
+}
+
+// X509KeySet returns the shared key set for an x509 certificate URL
+func X509KeySet(certsURL string) *KeySet {
+	return loadKeySet("x509:"+certsURL, func() *KeySet {
+		return NewKeySet(NewX509Client(certsURL).FetchKeys, KeySetOptions{})
+	})
+}
+
+func loadKeySet(name string, create func() *KeySet) *KeySet {
+	if keySet, ok := keySets.Load(name); ok {
+		return keySet.(*KeySet)
+	}
+	keySet, _ := keySets.LoadOrStore(name, create())
+	return keySet.(*KeySet)
+}
+
+// Key returns the public key for kid, fetching or refreshing the key set when needed
+func (s *KeySet) Key(ctx context.Context, kid string) (PublicKey, error) {
+	s.mu.RLock()
+	loaded, expiresAt := s.keys != nil, s.expiresAt
+	s.mu.RUnlock()
+
+	now := time.Now()
+	switch {
+	case !loaded || now.After(expiresAt.Add(s.options.MaxStale)):
+		if err := s.refresh(ctx); err != nil {
+			return PublicKey{}, err
+		}
+	case now.After(expiresAt.Add(-keySetRefreshAhead)):
+		s.refreshInBackground()
+	}
+
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+
+	// The key may have been rotated since the last fetch, refetch once
+	if err := s.refresh(ctx); err != nil {
+		return PublicKey{}, err
+	}
+
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+
+	return PublicKey{}, fmt.Errorf("key with kid %s not found in key set", kid)
+}
+
+func (s *KeySet) lookup(kid string) (PublicKey, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// refresh fetches the key set unless it was attempted within MinRefreshInterval.
+// Fetch errors keep the last good key set in place.
+func (s *KeySet) refresh(ctx context.Context) error {
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+
+	s.mu.RLock()
+	loaded, expiresAt, lastAttempt := s.keys != nil, s.expiresAt, s.lastAttempt
+	s.mu.RUnlock()
+
+	fresh := time.Now().Before(expiresAt.Add(s.options.MaxStale))
+	if loaded && fresh && time.Since(lastAttempt) < s.options.MinRefreshInterval {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, keySetFetchTimeout)
+	defer cancel()
+
+	keys, keysExpiresAt, err := s.fetch(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastAttempt = time.Now()
+	if err != nil {
+		if loaded && fresh {
+			return nil
+		}
+		return fmt.Errorf("failed to fetch key set: %w", err)
+	}
+
+	if keysExpiresAt.IsZero() {
+		keysExpiresAt = s.lastAttempt.Add(s.options.DefaultTTL)
+	}
+	s.keys = keys
+	s.expiresAt = keysExpiresAt
+
+	return nil
+}
+
+func (s *KeySet) refreshInBackground() {
+	s.mu.Lock()
+	if s.refreshing {
+		s.mu.Unlock()
+		return
+	}
+	s.refreshing = true
+	s.mu.Unlock()
+
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			s.refreshing = false
+			s.mu.Unlock()
+		}()
+
+		ctx := context.Background()
+		if err := s.refresh(ctx); err != nil {
+			log.WithContext(ctx).Warnf("failed to refresh key set: %v", err)
+		}
+	}()
+}
+
+// cacheExpiry returns when a response expires according to its Cache-Control or Expires header
+func cacheExpiry(header http.Header) time.Time {
+	now := time.Now()
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-store" || directive == "no-cache":
+			return now
+		case strings.HasPrefix(directive, "max-age="):
+			seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+			if err == nil && seconds >= 0 {
+				return now.Add(time.Duration(seconds) * time.Second)
+			}
+		}
+	}
+
+	if expires := header.Get("Expires"); expires != "" {
+		if expiresAt, err := http.ParseTime(expires); err == nil {
+			return expiresAt
+		}
+	}
+
+	return time.Time{}
+}
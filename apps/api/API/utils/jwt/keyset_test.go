@@ -0,0 +1,94 @@
+package jwt_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/utils/jwt"
+)
+
+func TestKeySet(t *testing.T) {
+	Convey("Test Key Set", t, func() {
+		ctx := context.Background()
+
+		var fetchCount int32
+		var fetchErr error
+		keys := map[string]jwt.PublicKey{"kid-1": {Key: "key-1", Algorithm: "RS256"}}
+		fetcher := func(ctx context.Context) (map[string]jwt.PublicKey, time.Time, error) {
+			atomic.AddInt32(&fetchCount, 1)
+			if fetchErr != nil {
+				return nil, time.Time{}, fetchErr
+			}
+			return keys, time.Time{}, nil
+		}
+
+		Convey("Caches keys between lookups", func() {
+			keySet := jwt.NewKeySet(fetcher, jwt.KeySetOptions{})
+
+			for i := 0; i < 3; i++ {
+				key, err := keySet.Key(ctx, "kid-1")
+				So(err, ShouldBeNil)
+				So(key.Key, ShouldEqual, "key-1")
+			}
+			So(atomic.LoadInt32(&fetchCount), ShouldEqual, 1)
+		})
+
+		Convey("Refetches once on unknown kid and rate-limits further refetches", func() {
+			keySet := jwt.NewKeySet(fetcher, jwt.KeySetOptions{MinRefreshInterval: time.Hour})
+
+			_, err := keySet.Key(ctx, "kid-1")
+			So(err, ShouldBeNil)
+
+			_, err = keySet.Key(ctx, "kid-unknown")
+			So(err, ShouldNotBeNil)
+			_, err = keySet.Key(ctx, "kid-unknown")
+			So(err, ShouldNotBeNil)
+			So(atomic.LoadInt32(&fetchCount), ShouldEqual, 1)
+		})
+
+		Convey("Picks up rotated keys on unknown kid", func() {
+			keySet := jwt.NewKeySet(fetcher, jwt.KeySetOptions{MinRefreshInterval: time.Millisecond})
+
+			_, err := keySet.Key(ctx, "kid-1")
+			So(err, ShouldBeNil)
+
+			time.Sleep(5 * time.Millisecond)
+			keys = map[string]jwt.PublicKey{"kid-2": {Key: "key-2", Algorithm: "RS256"}}
+
+			key, err := keySet.Key(ctx, "kid-2")
+			So(err, ShouldBeNil)
+			So(key.Key, ShouldEqual, "key-2")
+			So(atomic.LoadInt32(&fetchCount), ShouldEqual, 2)
+		})
+
+		Convey("Keeps serving the last good keys when the source is down", func() {
+			keySet := jwt.NewKeySet(fetcher, jwt.KeySetOptions{
+				DefaultTTL:         time.Millisecond,
+				MinRefreshInterval: time.Millisecond,
+			})
+
+			_, err := keySet.Key(ctx, "kid-1")
+			So(err, ShouldBeNil)
+
+			time.Sleep(5 * time.Millisecond)
+			fetchErr = errors.New("source down")
+
+			key, err := keySet.Key(ctx, "kid-1")
+			So(err, ShouldBeNil)
+			So(key.Key, ShouldEqual, "key-1")
+		})
+
+		Convey("Fails when the first fetch fails", func() {
+			fetchErr = errors.New("source down")
+			keySet := jwt.NewKeySet(fetcher, jwt.KeySetOptions{})
+
+			_, err := keySet.Key(ctx, "kid-1")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
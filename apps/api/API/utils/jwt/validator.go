@@ -2,15 +2,24 @@ package jwt
 
 import (
 	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rsa"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+
+	"prabogo/utils"
 )
 
 // JWKSet represents a JSON Web Key Set
@@ -23,10 +32,32 @@ type JWK struct {
 	Kid string `json:"kid"`
 	Kty string `json:"kty"`
 	Use string `json:"use"`
-	N   string `json:"n"`
-	E   string `json:"e"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// PublicKey is a verification key together with the only algorithm it may verify
+type PublicKey struct {
+	Key       crypto.PublicKey
+	Algorithm string
 }
 
+// keyAlgorithms lists the algorithms each key type and curve may be used with, the first is the default
+var keyAlgorithms = map[string][]string{
+	"RSA":     {"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"},
+	"P-256":   {"ES256"},
+	"P-384":   {"ES384"},
+	"P-521":   {"ES512"},
+	"Ed25519": {"EdDSA"},
+}
+
+// supportedAlgorithms lists every algorithm a key may be bound to
+var supportedAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
 // JWKSClient handles fetching and caching JWKS
 type JWKSClient struct {
 	jwksURL string
@@ -45,35 +76,103 @@ func NewJWKSClient(jwksURL string) *JWKSClient {
 
 // GetJWKSet fetches the JWKS from the URL
 func (c *JWKSClient) GetJWKSet(ctx context.Context) (*JWKSet, error) {
+	jwkSet, _, err := c.getJWKSet(ctx)
+	return jwkSet, err
+}
+
+// FetchKeys fetches the JWKS and returns its public keys by kid, it implements KeyFetcher
+func (c *JWKSClient) FetchKeys(ctx context.Context) (map[string]PublicKey, time.Time, error) {
+	jwkSet, expiresAt, err := c.getJWKSet(ctx)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	keys := make(map[string]PublicKey, len(jwkSet.Keys))
+	for _, jwk := range jwkSet.Keys {
+		if jwk.Use == "enc" {
+			continue
+		}
+
+		key, err := jwk.GetPublicKey()
+		if err != nil {
+			// Skip keys we cannot use instead of failing the whole set
+			continue
+		}
+
+		algorithm, err := jwk.Algorithm()
+		if err != nil {
+			continue
+		}
+
+		keys[jwk.Kid] = PublicKey{Key: key, Algorithm: algorithm}
+	}
+
+	return keys, expiresAt, nil
+}
+
+func (c *JWKSClient) getJWKSet(ctx context.Context) (*JWKSet, time.Time, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", c.jwksURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, time.Time{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+		return nil, time.Time{}, fmt.Errorf("failed to fetch JWKS: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("JWKS endpoint returned status %d", resp.StatusCode)
+		return nil, time.Time{}, fmt.Errorf("JWKS endpoint returned status %d", resp.StatusCode)
 	}
 
 	var jwkSet JWKSet
 	if err := json.NewDecoder(resp.Body).Decode(&jwkSet); err != nil {
-		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+		return nil, time.Time{}, fmt.Errorf("failed to decode JWKS: %w", err)
 	}
 
-	return &jwkSet, nil
+	return &jwkSet, cacheExpiry(resp.Header), nil
 }
 
-// GetPublicKey converts JWK to RSA public key
-func (jwk *JWK) GetPublicKey() (*rsa.PublicKey, error) {
-	if jwk.Kty != "RSA" {
+// GetPublicKey converts JWK to an RSA, ECDSA or Ed25519 public key
+func (jwk *JWK) GetPublicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		return jwk.getRSAPublicKey()
+	case "EC":
+		return jwk.getECPublicKey()
+	case "OKP":
+		return jwk.getOKPPublicKey()
+	default:
 		return nil, fmt.Errorf("unsupported key type: %s", jwk.Kty)
 	}
+}
 
+// Algorithm returns the signing algorithm bound to the key, taken from alg when
+// present and otherwise from the key type and curve
+func (jwk *JWK) Algorithm() (string, error) {
+	family := jwk.Crv
+	if jwk.Kty == "RSA" {
+		family = jwk.Kty
+	}
+
+	algorithms, ok := keyAlgorithms[family]
+	if !ok {
+		return "", fmt.Errorf("unsupported key type: %s %s", jwk.Kty, jwk.Crv)
+	}
+
+	if jwk.Alg == "" {
+		return algorithms[0], nil
+	}
+
+	if !utils.IsInList(algorithms, jwk.Alg) {
+		return "", fmt.Errorf("algorithm %s cannot be used with key type %s", jwk.Alg, family)
+	}
+
+	return jwk.Alg, nil
+}
+
+func (jwk *JWK) getRSAPublicKey() (*rsa.PublicKey, error) {
 	// Decode the modulus
 	nBytes, err := base64.RawURLEncoding.DecodeString(jwk.N)
 	if err != nil {
@@ -103,74 +202,118 @@ func (jwk *JWK) GetPublicKey() (*rsa.PublicKey, error) {
 	}, nil
 }
 
-// ValidateJWTWithURL validates the JWT token with a specific JWKS URL
-// Returns (isValid bool, error)
-func ValidateJWTWithURL(tokenString, jwksURL string) (bool, error) {
-	// Create JWKS client
-	jwksClient := NewJWKSClient(jwksURL)
+func (jwk *JWK) getECPublicKey() (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	var ecdhCurve ecdh.Curve
+	switch jwk.Crv {
+	case "P-256":
+		curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+	case "P-384":
+		curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+	case "P-521":
+		curve, ecdhCurve = elliptic.P521(), ecdh.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve: %s", jwk.Crv)
+	}
 
-	// Parse the token to get the header and validate
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// Validate the signing method
-		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
+	xBytes, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode x coordinate: %w", err)
+	}
+
+	yBytes, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode y coordinate: %w", err)
+	}
+
+	size := (curve.Params().BitSize + 7) / 8
+	if len(xBytes) != size || len(yBytes) != size {
+		return nil, fmt.Errorf("invalid coordinate length for curve %s", jwk.Crv)
+	}
 
+	// Reject points that are not on the curve
+	point := append([]byte{4}, append(xBytes, yBytes...)...)
+	if _, err := ecdhCurve.NewPublicKey(point); err != nil {
+		return nil, fmt.Errorf("invalid point for curve %s: %w", jwk.Crv, err)
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(xBytes),
+		Y:     new(big.Int).SetBytes(yBytes),
+	}, nil
+}
+
+func (jwk *JWK) getOKPPublicKey() (ed25519.PublicKey, error) {
+	if jwk.Crv != "Ed25519" {
+		return nil, fmt.Errorf("unsupported curve: %s", jwk.Crv)
+	}
+
+	xBytes, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode x coordinate: %w", err)
+	}
+
+	if len(xBytes) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid key length for curve %s", jwk.Crv)
+	}
+
+	return ed25519.PublicKey(xBytes), nil
+}
+
+// keyFunc returns a jwt.Keyfunc that resolves the token kid in the key set and only
+// accepts the algorithm bound to that key, never the one claimed by the token header
+func keyFunc(keySet *KeySet) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
 		// Get the kid from the token header
 		kid, ok := token.Header["kid"].(string)
 		if !ok {
 			return nil, fmt.Errorf("kid not found in token header")
 		}
 
-		// Fetch JWKS
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
-
-		jwkSet, err := jwksClient.GetJWKSet(ctx)
+		key, err := keySet.Key(context.Background(), kid)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+			return nil, err
 		}
 
-		// Find the matching key
-		for _, jwk := range jwkSet.Keys {
-			if jwk.Kid == kid {
-				return jwk.GetPublicKey()
-			}
+		// Validate the signing method against the key
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
 
-		return nil, fmt.Errorf("key with kid %s not found in JWKS", kid)
-	})
-
-	if err != nil {
-		return false, fmt.Errorf("failed to parse/validate token: %w", err)
-	}
-
-	if !token.Valid {
-		return false, fmt.Errorf("token is not valid")
+		return key.Key, nil
 	}
+}
 
-	// Extract claims to check expiration
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return false, fmt.Errorf("failed to parse claims")
-	}
+// Validation errors, match them with errors.Is to get the reason a token was rejected
+var (
+	ErrTokenExpired         = jwt.ErrTokenExpired
+	ErrTokenNotValidYet     = jwt.ErrTokenNotValidYet
+	ErrTokenInvalidIssuer   = jwt.ErrTokenInvalidIssuer
+	ErrTokenInvalidAudience = jwt.ErrTokenInvalidAudience
+	ErrTokenMissingScope    = errors.New("token is missing required scope")
+	ErrTokenInvalidClaim    = errors.New("token has invalid or missing required claim")
+)
 
-	// Check token expiration
-	if exp, ok := claims["exp"].(float64); ok {
-		expTime := time.Unix(int64(exp), 0)
-		if time.Now().After(expTime) {
-			return false, fmt.Errorf("token has expired at %s", expTime.Format(time.RFC3339))
-		}
-	} else {
-		return false, fmt.Errorf("token does not contain expiration claim")
-	}
+// ValidationOptions configures the claim checks applied after the signature is verified
+type ValidationOptions struct {
+	// Issuer is the required iss claim, empty skips the check
+	Issuer string
+	// Audiences lists the accepted aud values, the token must contain at least one
+	Audiences []string
+	// Scopes lists the scopes the token must grant through scope or scp
+	Scopes []string
+	// Claims lists required claims, an empty value only requires the claim to be present
+	Claims map[string]string
+	// Leeway is the allowed clock skew for exp, nbf and iat
+	Leeway time.Duration
+}
 
-	// Check not before time if present
-	if nbf, ok := claims["nbf"].(float64); ok {
-		nbfTime := time.Unix(int64(nbf), 0)
-		if time.Now().Before(nbfTime) {
-			return false, fmt.Errorf("token is not valid before %s", nbfTime.Format(time.RFC3339))
-		}
+// ValidateJWTWithURL validates the JWT token with a specific JWKS URL
+// Returns (isValid bool, error)
+func ValidateJWTWithURL(tokenString, jwksURL string) (bool, error) {
+	if _, err := GetJWTClaimsWithURL(tokenString, jwksURL); err != nil {
+		return false, err
 	}
 
 	return true, nil
@@ -178,37 +321,34 @@ func ValidateJWTWithURL(tokenString, jwksURL string) (bool, error) {
 
 // GetJWTClaimsWithURL validates JWT with specific URL and returns the claims map
 func GetJWTClaimsWithURL(tokenString, jwksURL string) (jwt.MapClaims, error) {
-	// First validate the token
-	isValid, err := ValidateJWTWithURL(tokenString, jwksURL)
-	if !isValid && err != nil {
-		return nil, err
-	}
-
-	// Parse token again to get claims (we know it's valid now)
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// We already validated, so just get the key again
-		jwksClient := NewJWKSClient(jwksURL)
-		kid := token.Header["kid"].(string)
-
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+	return GetJWTClaimsWithOptions(tokenString, jwksURL, ValidationOptions{})
+}
 
-		jwkSet, jwkErr := jwksClient.GetJWKSet(ctx)
-		if jwkErr != nil {
-			return nil, jwkErr
-		}
+// GetJWTClaimsWithOptions validates JWT with specific URL and options and returns the claims map
+func GetJWTClaimsWithOptions(tokenString, jwksURL string, options ValidationOptions) (jwt.MapClaims, error) {
+	keySet := JWKSKeySet(jwksURL)
 
-		for _, jwk := range jwkSet.Keys {
-			if jwk.Kid == kid {
-				return jwk.GetPublicKey()
-			}
-		}
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods(supportedAlgorithms),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(options.Leeway),
+	}
+	if options.Issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(options.Issuer))
+	}
+	if len(options.Audiences) > 0 {
+		parserOptions = append(parserOptions, jwt.WithAudience(options.Audiences...))
+	}
 
-		return nil, fmt.Errorf("key not found")
-	})
+	// Parse the token to get the header and validate
+	token, err := jwt.NewParser(parserOptions...).Parse(tokenString, keyFunc(keySet))
 
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to parse/validate token: %w", err)
+	}
+
+	if !token.Valid {
+		return nil, fmt.Errorf("token is not valid")
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
@@ -216,5 +356,39 @@ func GetJWTClaimsWithURL(tokenString, jwksURL string) (jwt.MapClaims, error) {
 		return nil, fmt.Errorf("failed to parse claims")
 	}
 
+	// Check required scopes
+	granted := GetScopes(claims)
+	for _, scope := range options.Scopes {
+		if !utils.IsInList(granted, scope) {
+			return nil, fmt.Errorf("%w: %s", ErrTokenMissingScope, scope)
+		}
+	}
+
+	// Check required claims
+	for name, expected := range options.Claims {
+		value, ok := claims[name]
+		if !ok || (expected != "" && fmt.Sprint(value) != expected) {
+			return nil, fmt.Errorf("%w: %s", ErrTokenInvalidClaim, name)
+		}
+	}
+
 	return claims, nil
 }
+
+// GetScopes returns the scopes granted by the space separated scope claim or the scp claim
+func GetScopes(claims jwt.MapClaims) []string {
+	var scopes []string
+	for _, name := range []string{"scope", "scp"} {
+		switch value := claims[name].(type) {
+		case string:
+			scopes = append(scopes, strings.Fields(value)...)
+		case []interface{}:
+			for _, scope := range value {
+				if scope, ok := scope.(string); ok {
+					scopes = append(scopes, scope)
+				}
+			}
+		}
+	}
+	return scopes
+}
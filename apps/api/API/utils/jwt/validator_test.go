@@ -0,0 +1,265 @@
+package jwt_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/utils/jwt"
+)
+
+func TestValidateJWTWithURL(t *testing.T) {
+	Convey("Test Validate JWT With URL", t, func() {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		So(err, ShouldBeNil)
+
+		var fetchCount int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&fetchCount, 1)
+			w.Header().Set("Cache-Control", "public, max-age=3600")
+			_ = json.NewEncoder(w).Encode(jwt.JWKSet{Keys: []jwt.JWK{{
+				Kid: "kid-1",
+				Kty: "RSA",
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(privateKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(privateKey.E)).Bytes()),
+			}}})
+		}))
+		defer server.Close()
+
+		now := time.Now()
+		claims := jwtlib.MapClaims{
+			"iss":   "https://idp.kost.test/",
+			"aud":   []string{"kost-api", "kost-admin"},
+			"sub":   "subject",
+			"scope": "openid clients:read",
+			"role":  "owner",
+			"exp":   now.Add(time.Hour).Unix(),
+		}
+		sign := func(claims jwtlib.MapClaims) string {
+			token := jwtlib.NewWithClaims(jwtlib.SigningMethodRS256, claims)
+			token.Header["kid"] = "kid-1"
+			tokenString, err := token.SignedString(privateKey)
+			So(err, ShouldBeNil)
+			return tokenString
+		}
+
+		Convey("Fetches the JWKS once for repeated validations", func() {
+			tokenString := sign(claims)
+			for i := 0; i < 3; i++ {
+				valid, err := jwt.ValidateJWTWithURL(tokenString, server.URL)
+				So(err, ShouldBeNil)
+				So(valid, ShouldBeTrue)
+			}
+
+			claims, err := jwt.GetJWTClaimsWithURL(tokenString, server.URL)
+			So(err, ShouldBeNil)
+			So(claims["sub"], ShouldEqual, "subject")
+			So(atomic.LoadInt32(&fetchCount), ShouldEqual, 1)
+		})
+
+		Convey("With options", func() {
+			options := jwt.ValidationOptions{
+				Issuer:    "https://idp.kost.test/",
+				Audiences: []string{"kost-api"},
+				Scopes:    []string{"clients:read"},
+				Claims:    map[string]string{"role": "owner", "sub": ""},
+			}
+
+			Convey("Valid token", func() {
+				result, err := jwt.GetJWTClaimsWithOptions(sign(claims), server.URL, options)
+				So(err, ShouldBeNil)
+				So(result["sub"], ShouldEqual, "subject")
+			})
+
+			Convey("Wrong issuer", func() {
+				claims["iss"] = "https://other.test/"
+				_, err := jwt.GetJWTClaimsWithOptions(sign(claims), server.URL, options)
+				So(errors.Is(err, jwt.ErrTokenInvalidIssuer), ShouldBeTrue)
+			})
+
+			Convey("Token meant for another application", func() {
+				claims["aud"] = "other-app"
+				_, err := jwt.GetJWTClaimsWithOptions(sign(claims), server.URL, options)
+				So(errors.Is(err, jwt.ErrTokenInvalidAudience), ShouldBeTrue)
+			})
+
+			Convey("Missing scope", func() {
+				claims["scope"] = "openid"
+				_, err := jwt.GetJWTClaimsWithOptions(sign(claims), server.URL, options)
+				So(errors.Is(err, jwt.ErrTokenMissingScope), ShouldBeTrue)
+			})
+
+			Convey("Scopes from scp claim", func() {
+				delete(claims, "scope")
+				claims["scp"] = []string{"clients:read"}
+				_, err := jwt.GetJWTClaimsWithOptions(sign(claims), server.URL, options)
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Invalid claim value", func() {
+				claims["role"] = "tenant"
+				_, err := jwt.GetJWTClaimsWithOptions(sign(claims), server.URL, options)
+				So(errors.Is(err, jwt.ErrTokenInvalidClaim), ShouldBeTrue)
+			})
+
+			Convey("Expired token", func() {
+				claims["exp"] = now.Add(-10 * time.Second).Unix()
+				_, err := jwt.GetJWTClaimsWithOptions(sign(claims), server.URL, options)
+				So(errors.Is(err, jwt.ErrTokenExpired), ShouldBeTrue)
+			})
+
+			Convey("Expired token within leeway", func() {
+				claims["exp"] = now.Add(-10 * time.Second).Unix()
+				options.Leeway = time.Minute
+				_, err := jwt.GetJWTClaimsWithOptions(sign(claims), server.URL, options)
+				So(err, ShouldBeNil)
+			})
+
+			Convey("Not valid yet", func() {
+				claims["nbf"] = now.Add(time.Hour).Unix()
+				_, err := jwt.GetJWTClaimsWithOptions(sign(claims), server.URL, options)
+				So(errors.Is(err, jwt.ErrTokenNotValidYet), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestJWKAlgorithms(t *testing.T) {
+	Convey("Test JWK Algorithms", t, func() {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		So(err, ShouldBeNil)
+		p256Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		So(err, ShouldBeNil)
+		p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		So(err, ShouldBeNil)
+		p521Key, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+		So(err, ShouldBeNil)
+		edPublicKey, edPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+		So(err, ShouldBeNil)
+
+		ecJWK := func(kid, crv string, key *ecdsa.PrivateKey) jwt.JWK {
+			size := (key.Curve.Params().BitSize + 7) / 8
+			return jwt.JWK{
+				Kid: kid,
+				Kty: "EC",
+				Crv: crv,
+				X:   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, size))),
+				Y:   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, size))),
+			}
+		}
+
+		jwkSet := jwt.JWKSet{Keys: []jwt.JWK{
+			{
+				Kid: "rsa",
+				Kty: "RSA",
+				Alg: "RS256",
+				N:   base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+			},
+			ecJWK("p256", "P-256", p256Key),
+			ecJWK("p384", "P-384", p384Key),
+			ecJWK("p521", "P-521", p521Key),
+			{
+				Kid: "ed25519",
+				Kty: "OKP",
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(edPublicKey),
+			},
+		}}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(jwkSet)
+		}))
+		defer server.Close()
+
+		sign := func(method jwtlib.SigningMethod, kid string, key crypto.PrivateKey) string {
+			token := jwtlib.NewWithClaims(method, jwtlib.MapClaims{
+				"sub": "subject",
+				"exp": time.Now().Add(time.Hour).Unix(),
+			})
+			token.Header["kid"] = kid
+			tokenString, err := token.SignedString(key)
+			So(err, ShouldBeNil)
+			return tokenString
+		}
+
+		Convey("Accepts the algorithm bound to each key", func() {
+			tokens := []string{
+				sign(jwtlib.SigningMethodRS256, "rsa", rsaKey),
+				sign(jwtlib.SigningMethodES256, "p256", p256Key),
+				sign(jwtlib.SigningMethodES384, "p384", p384Key),
+				sign(jwtlib.SigningMethodES512, "p521", p521Key),
+				sign(jwtlib.SigningMethodEdDSA, "ed25519", edPrivateKey),
+			}
+			for _, tokenString := range tokens {
+				valid, err := jwt.ValidateJWTWithURL(tokenString, server.URL)
+				So(err, ShouldBeNil)
+				So(valid, ShouldBeTrue)
+			}
+		})
+
+		Convey("Rejects an algorithm other than the one bound to the key", func() {
+			_, err := jwt.ValidateJWTWithURL(sign(jwtlib.SigningMethodRS512, "rsa", rsaKey), server.URL)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Rejects HMAC signed with the public key", func() {
+			publicKeyBytes, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+			So(err, ShouldBeNil)
+
+			_, err = jwt.ValidateJWTWithURL(sign(jwtlib.SigningMethodHS256, "rsa", publicKeyBytes), server.URL)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Rejects a token claiming another curve", func() {
+			_, err := jwt.ValidateJWTWithURL(sign(jwtlib.SigningMethodES384, "p256", p384Key), server.URL)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Parses keys", func() {
+			Convey("EC point not on the curve", func() {
+				jwk := ecJWK("p256", "P-256", p256Key)
+				jwk.Y = jwk.X
+				_, err := jwk.GetPublicKey()
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Unsupported curve", func() {
+				jwk := ecJWK("p256", "P-256", p256Key)
+				jwk.Crv = "secp256k1"
+				_, err := jwk.GetPublicKey()
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Algorithm incompatible with key type", func() {
+				jwk := ecJWK("p256", "P-256", p256Key)
+				jwk.Alg = "RS256"
+				_, err := jwk.Algorithm()
+				So(err, ShouldNotBeNil)
+			})
+
+			Convey("Default algorithm from curve", func() {
+				jwk := ecJWK("p384", "P-384", p384Key)
+				algorithm, err := jwk.Algorithm()
+				So(err, ShouldBeNil)
+				So(algorithm, ShouldEqual, "ES384")
+			})
+		})
+	})
+}
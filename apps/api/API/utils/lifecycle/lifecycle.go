@@ -0,0 +1,92 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+type hook struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// Manager stops the parts of a process in the reverse order they were started, so
+// inbound servers drain before the connections they use are closed
+type Manager struct {
+	timeout time.Duration
+	mu      sync.Mutex
+	hooks   []hook
+	stopped bool
+}
+
+// New creates a manager whose parts get timeout to stop, 0 waits for them
+func New(timeout time.Duration) *Manager {
+	return &Manager{timeout: timeout}
+}
+
+// Timeout is how long an inbound gets to drain, and then the stop hooks to run
+func (m *Manager) Timeout() time.Duration {
+	return m.timeout
+}
+
+// OnStop registers stop to run on Shutdown, after every hook registered later
+func (m *Manager) OnStop(name string, stop func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook{name: name, stop: stop})
+}
+
+// Shutdown runs the stop hooks once, latest first. A hook still running when ctx is
+// done is abandoned and the next one runs, so every connection gets a chance to close.
+// The errors of all hooks are joined.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	if m.stopped {
+		m.mu.Unlock()
+		return nil
+	}
+	m.stopped = true
+	hooks := m.hooks
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := run(ctx, hooks[i]); err != nil {
+			errs = append(errs, fmt.Errorf("stop %s: %w", hooks[i].name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func run(ctx context.Context, h hook) error {
+	if ctx.Err() != nil {
+		// keep closing what is left, but do not wait on it
+		go h.stop(ctx)
+		return ctx.Err()
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- h.stop(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SignalContext returns a copy of ctx that is done on SIGINT or SIGTERM. Calling stop
+// restores the default handling, so a second signal ends the process at once.
+func SignalContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+}
@@ -0,0 +1,70 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/utils/lifecycle"
+)
+
+func TestManager(t *testing.T) {
+	Convey("Test Lifecycle Manager", t, func() {
+		manager := lifecycle.New(0)
+		var stopped []string
+		stopper := func(name string, err error) func(context.Context) error {
+			return func(context.Context) error {
+				stopped = append(stopped, name)
+				return err
+			}
+		}
+
+		Convey("Stops in reverse order once", func() {
+			manager.OnStop("database", stopper("database", nil))
+			manager.OnStop("cache", stopper("cache", nil))
+			manager.OnStop("http server", stopper("http server", nil))
+
+			So(manager.Shutdown(context.Background()), ShouldBeNil)
+			So(manager.Shutdown(context.Background()), ShouldBeNil)
+			So(stopped, ShouldResemble, []string{"http server", "cache", "database"})
+		})
+
+		Convey("Joins errors and keeps stopping", func() {
+			manager.OnStop("database", stopper("database", nil))
+			manager.OnStop("cache", stopper("cache", errors.New("connection reset")))
+
+			err := manager.Shutdown(context.Background())
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "stop cache: connection reset")
+			So(stopped, ShouldResemble, []string{"cache", "database"})
+		})
+
+		Convey("Abandons a hook past the deadline", func() {
+			closed := make(chan struct{})
+			manager.OnStop("database", func(context.Context) error {
+				close(closed)
+				return nil
+			})
+			manager.OnStop("subscriber", func(context.Context) error {
+				time.Sleep(time.Second)
+				return nil
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			err := manager.Shutdown(ctx)
+			So(errors.Is(err, context.DeadlineExceeded), ShouldBeTrue)
+			So(err.Error(), ShouldContainSubstring, "stop subscriber")
+
+			select {
+			case <-closed:
+			case <-time.After(time.Second):
+				t.Fatal("database was not closed")
+			}
+		})
+	})
+}
@@ -0,0 +1,131 @@
+package lru
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache is a size bounded cache that evicts the least recently used entry when full and
+// drops entries once their TTL has passed. Its version changes on every delete, so a
+// value loaded before a delete is not stored after it, see SetIf.
+type Cache[V any] struct {
+	mu      sync.Mutex
+	version int64
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type entry[V any] struct {
+	key       string
+	value     V
+	expiresAt time.Time
+}
+
+// New creates a cache of up to size entries, unbounded when size is not positive
+func New[V any](size int) *Cache[V] {
+	return &Cache[V]{
+		size:    size,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+func (c *Cache[V]) Get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	element, ok := c.entries[key]
+	if !ok {
+		return zero, false
+	}
+
+	e := element.Value.(*entry[V])
+	if !time.Now().Before(e.expiresAt) {
+		c.remove(element)
+		return zero, false
+	}
+
+	c.order.MoveToFront(element)
+	return e.value, true
+}
+
+func (c *Cache[V]) Set(key string, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.put(key, value, ttl)
+}
+
+// Version returns the current version, read it before loading a value for SetIf
+func (c *Cache[V]) Version() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.version
+}
+
+// SetIf sets the entry unless entries were deleted since version, and reports whether it did
+func (c *Cache[V]) SetIf(version int64, key string, value V, ttl time.Duration) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.version != version {
+		return false
+	}
+	c.put(key, value, ttl)
+	return true
+}
+
+func (c *Cache[V]) Delete(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version++
+	for _, key := range keys {
+		if element, ok := c.entries[key]; ok {
+			c.remove(element)
+		}
+	}
+}
+
+// DeleteFunc removes the entries whose value matches
+func (c *Cache[V]) DeleteFunc(match func(value V) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version++
+	for element := c.order.Front(); element != nil; {
+		next := element.Next()
+		if match(element.Value.(*entry[V]).value) {
+			c.remove(element)
+		}
+		element = next
+	}
+}
+
+func (c *Cache[V]) put(key string, value V, ttl time.Duration) {
+	e := &entry[V]{
+		key:       key,
+		value:     value,
+		expiresAt: time.Now().Add(ttl),
+	}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = e
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(e)
+	for c.size > 0 && c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *Cache[V]) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*entry[V]).key)
+}
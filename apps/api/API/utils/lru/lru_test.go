@@ -0,0 +1,51 @@
+package lru_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/utils/lru"
+)
+
+func TestCache(t *testing.T) {
+	Convey("Test LRU Cache", t, func() {
+		cache := lru.New[int](2)
+
+		Convey("Evicts the least recently used entry when full", func() {
+			cache.Set("a", 1, time.Minute)
+			cache.Set("b", 2, time.Minute)
+			_, ok := cache.Get("a")
+			So(ok, ShouldBeTrue)
+			cache.Set("c", 3, time.Minute)
+
+			_, ok = cache.Get("b")
+			So(ok, ShouldBeFalse)
+			value, ok := cache.Get("a")
+			So(ok, ShouldBeTrue)
+			So(value, ShouldEqual, 1)
+		})
+
+		Convey("Drops entries past their TTL", func() {
+			cache.Set("a", 1, 10*time.Millisecond)
+			time.Sleep(20 * time.Millisecond)
+
+			_, ok := cache.Get("a")
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("SetIf skips values loaded before a delete", func() {
+			version := cache.Version()
+			cache.DeleteFunc(func(value int) bool { return value == 1 })
+
+			So(cache.SetIf(version, "a", 1, time.Minute), ShouldBeFalse)
+			_, ok := cache.Get("a")
+			So(ok, ShouldBeFalse)
+
+			So(cache.SetIf(cache.Version(), "a", 1, time.Minute), ShouldBeTrue)
+			_, ok = cache.Get("a")
+			So(ok, ShouldBeTrue)
+		})
+	})
+}
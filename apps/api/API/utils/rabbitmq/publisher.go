@@ -22,18 +22,25 @@ func (p *publisher) Publish(ctx context.Context, exchange string, exchangeKind E
 	return Publish(ctx, exchange, exchangeKind, routeKey, msg)
 }
 
-func Publish(ctx context.Context, exchange string, exchangeKind ExchangeKind, routeKey string, msg any) (err error) {
+func Publish(ctx context.Context, exchange string, exchangeKind ExchangeKind, routeKey string, msg any) error {
 	msgBytes, err := json.Marshal(msg)
 	if err != nil {
 		return err
 	}
 
-	// Use global connection from InitMessage (singleton)
-	if initErr := InitMessage(); initErr != nil {
-		return initErr
+	return PublishBody(ctx, exchange, exchangeKind, routeKey, "", msgBytes)
+}
+
+// PublishBody publishes a JSON body that is already encoded, messageID is set as the
+// message id when not empty
+func PublishBody(ctx context.Context, exchange string, exchangeKind ExchangeKind, routeKey string, messageID string, body []byte) (err error) {
+	// Use global connection from InitMessage (singleton), reconnecting when it closed
+	conn, err := connect()
+	if err != nil {
+		return err
 	}
 
-	ch, err := rabbitConn.Channel()
+	ch, err := conn.Channel()
 	if err != nil {
 		return err
 	}
@@ -65,7 +72,8 @@ func Publish(ctx context.Context, exchange string, exchangeKind ExchangeKind, ro
 		false,
 		amqp.Publishing{
 			ContentType: "application/json",
-			Body:        msgBytes,
+			MessageId:   messageID,
+			Body:        body,
 		})
 	if err != nil {
 		return err
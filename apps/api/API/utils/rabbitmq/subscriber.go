@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
+	"sync"
 
 	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
@@ -21,33 +21,75 @@ const (
 	KindHeaders ExchangeKind = "headers"
 )
 
+// Config is the connection of the broker
+type Config struct {
+	Host     string `env:"MESSAGE_HOST" validate:"required" description:"broker host"`
+	Port     string `env:"MESSAGE_PORT" default:"5672" description:"broker port"`
+	User     string `env:"MESSAGE_USER" description:"user name"`
+	Password string `env:"MESSAGE_PASSWORD" secret:"true" description:"user password"`
+	VHost    string `env:"MESSAGE_VHOST" description:"virtual host"`
+}
+
+func (c Config) url() string {
+	return fmt.Sprintf("amqp://%s:%s@%s:%s/%s", c.User, c.Password, c.Host, c.Port, c.VHost)
+}
+
 var (
+	// connMu guards rabbitConn and settings, publishers and subscribers share them
+	connMu     sync.Mutex
 	rabbitConn *amqp.Connection
+	// settings is kept so subscribers can reconnect
+	settings Config
 )
 
-func getUrl() string {
-	return fmt.Sprintf(
-		"amqp://%s:%s@%s:%s/%s",
-		os.Getenv("MESSAGE_USER"),
-		os.Getenv("MESSAGE_PASSWORD"),
-		os.Getenv("MESSAGE_HOST"),
-		os.Getenv("MESSAGE_PORT"),
-		os.Getenv("MESSAGE_VHOST"),
-	)
+// InitMessage opens the shared connection to the broker of cfg
+func InitMessage(cfg Config) error {
+	connMu.Lock()
+	settings = cfg
+	connMu.Unlock()
+
+	_, err := connect()
+	return err
 }
 
-func InitMessage() error {
+// connect returns the shared connection, dialing it when it is not open
+func connect() (*amqp.Connection, error) {
+	connMu.Lock()
+	defer connMu.Unlock()
+
 	if rabbitConn != nil && !rabbitConn.IsClosed() {
-		return nil
+		return rabbitConn, nil
 	}
-	conn, err := amqp.Dial(getUrl())
+	conn, err := amqp.Dial(settings.url())
 	if err != nil {
-		return err
+		return nil, err
 	}
 	rabbitConn = conn
+	return conn, nil
+}
+
+// Ping reports whether the shared connection is open
+func Ping() error {
+	connMu.Lock()
+	defer connMu.Unlock()
+
+	if rabbitConn == nil || rabbitConn.IsClosed() {
+		return errors.New("rabbitmq connection is closed")
+	}
 	return nil
 }
 
+// Close closes the shared connection, channels of running subscribers close with it
+func Close() error {
+	connMu.Lock()
+	defer connMu.Unlock()
+
+	if rabbitConn == nil || rabbitConn.IsClosed() {
+		return nil
+	}
+	return rabbitConn.Close()
+}
+
 type SubscriberConfig struct {
 	Exchange     string
 	ExchangeKind ExchangeKind
@@ -73,22 +115,22 @@ func (c *SubscriberConfig) Validate() error {
 	return nil
 }
 
-func SubscriberWithConfig(cfg SubscriberConfig) error {
+// SubscriberWithConfig consumes the queue of cfg until ctx is done. It then cancels the
+// consumer and returns once the deliveries already received are handled.
+func SubscriberWithConfig(ctx context.Context, cfg SubscriberConfig) error {
 	if err := cfg.Validate(); err != nil {
 		fmt.Printf("rabbitmq subscriber config error: %s\n", err.Error())
 		return err
 	}
 
 	fmt.Printf("rabbitmq subscriber config: %+v\n", cfg)
-	ctx := context.Background()
 
-	if rabbitConn == nil || rabbitConn.IsClosed() {
-		if err := InitMessage(); err != nil {
-			return fmt.Errorf("failed to init rabbitmq connection: %w", err)
-		}
+	conn, err := connect()
+	if err != nil {
+		return fmt.Errorf("failed to init rabbitmq connection: %w", err)
 	}
 
-	ch, err := rabbitConn.Channel()
+	ch, err := conn.Channel()
 	if err != nil {
 		return err
 	}
@@ -149,31 +191,40 @@ func SubscriberWithConfig(cfg SubscriberConfig) error {
 		return err
 	}
 
-	forever := make(chan struct{})
+	done := make(chan struct{})
 	go func() {
+		defer close(done)
 		for d := range msgs {
 			ack := cfg.Callback(d.Body)
 			if ack {
-				err = d.Ack(false)
-				if err != nil {
-					log.WithContext(context.Background()).Errorf("failed to ack message with body %s: %s", string(d.Body), err)
+				if errAck := d.Ack(false); errAck != nil {
+					log.WithContext(context.Background()).Errorf("failed to ack message with body %s: %s", string(d.Body), errAck)
 				}
 
 			} else {
-				err = d.Nack(false, true)
-				if err != nil {
-					log.WithContext(context.Background()).Errorf("failed to nack message with body %s: %s", string(d.Body), err)
+				if errNack := d.Nack(false, true); errNack != nil {
+					log.WithContext(context.Background()).Errorf("failed to nack message with body %s: %s", string(d.Body), errNack)
 				}
 			}
 		}
 	}()
 	log.WithContext(ctx).Infof("subscriber listen exchange: '%s', queue: '%s', topic: '%s', consumerKey: '%s'", cfg.Exchange, cfg.Queue, cfg.RouteKey, consumerKey)
-	<-forever
-	return nil
+
+	select {
+	case <-ctx.Done():
+		if err = ch.Cancel(consumerKey, false); err != nil {
+			return err
+		}
+		<-done
+		log.WithContext(ctx).Infof("subscriber stopped queue: '%s', consumerKey: '%s'", cfg.Queue, consumerKey)
+		return nil
+	case <-done:
+		return errors.New("subscriber deliveries closed")
+	}
 }
 
-func Subscriber(exchange string, exchangeKind ExchangeKind, queue, routeKey string, callback func(msg []byte) bool) error {
-	return SubscriberWithConfig(SubscriberConfig{
+func Subscriber(ctx context.Context, exchange string, exchangeKind ExchangeKind, queue, routeKey string, callback func(msg []byte) bool) error {
+	return SubscriberWithConfig(ctx, SubscriberConfig{
 		Exchange:     exchange,
 		ExchangeKind: exchangeKind,
 		Queue:        queue,
@@ -0,0 +1,172 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"prabogo/utils/log"
+	"prabogo/utils/redis"
+)
+
+// Limit allows Requests hits per sliding Window
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// Result is the outcome of a hit against a limit
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the oldest hit leaves the window
+	Reset time.Duration
+}
+
+// Store records hits in a sliding window and returns whether the hit was allowed,
+// the hits in the window and the time of the oldest hit
+type Store interface {
+	Hit(ctx context.Context, key string, limit Limit) (bool, int, time.Time, error)
+}
+
+// Limiter applies limits on a store and falls back to memory when the store fails
+type Limiter struct {
+	store    Store
+	fallback Store
+}
+
+// NewLimiter creates a limiter backed by store
+func NewLimiter(store Store) *Limiter {
+	return &Limiter{
+		store:    store,
+		fallback: NewMemoryStore(),
+	}
+}
+
+// Allow records a hit for key and reports whether it is within limit
+func (l *Limiter) Allow(ctx context.Context, key string, limit Limit) Result {
+	allowed, count, oldest, err := l.store.Hit(ctx, key, limit)
+	if err != nil {
+		log.WithContext(ctx).Warnf("rate limit store error, using memory: %v", err)
+		allowed, count, oldest, _ = l.fallback.Hit(ctx, key, limit)
+	}
+
+	reset := time.Until(oldest.Add(limit.Window))
+	if reset < 0 {
+		reset = 0
+	}
+
+	return Result{
+		Allowed:   allowed,
+		Limit:     limit.Requests,
+		Remaining: max(limit.Requests-count, 0),
+		Reset:     reset,
+	}
+}
+
+// ParseLimit parses a limit written as <requests>/<window>, e.g. 100/1m
+func ParseLimit(value string) (Limit, error) {
+	requests, window, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("rate limit %q is not <requests>/<window>", value)
+	}
+
+	limit := Limit{}
+	var err error
+	limit.Requests, err = strconv.Atoi(requests)
+	if err != nil || limit.Requests <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q has invalid requests", value)
+	}
+
+	limit.Window, err = time.ParseDuration(window)
+	if err != nil || limit.Window <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q has invalid window", value)
+	}
+
+	return limit, nil
+}
+
+type redisStore struct{}
+
+// NewRedisStore creates a store on the shared Redis cache client
+func NewRedisStore() Store {
+	return &redisStore{}
+}
+
+func (s *redisStore) Hit(ctx context.Context, key string, limit Limit) (bool, int, time.Time, error) {
+	return redis.SlidingWindow(ctx, "ratelimit:"+key, limit.Requests, limit.Window, uuid.New().String())
+}
+
+type memoryStore struct {
+	mu        sync.Mutex
+	windows   map[string]*memoryWindow
+	lastSweep time.Time
+}
+
+type memoryWindow struct {
+	hits   []time.Time
+	length time.Duration
+}
+
+// NewMemoryStore creates a store that keeps hits in process memory
+func NewMemoryStore() Store {
+	return &memoryStore{
+		windows:   map[string]*memoryWindow{},
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *memoryStore) Hit(ctx context.Context, key string, limit Limit) (bool, int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	window, ok := s.windows[key]
+	if !ok {
+		window = &memoryWindow{}
+		s.windows[key] = window
+	}
+	window.length = limit.Window
+	window.prune(now)
+
+	allowed := len(window.hits) < limit.Requests
+	if allowed {
+		window.hits = append(window.hits, now)
+	}
+
+	if len(window.hits) == 0 {
+		return allowed, 0, now, nil
+	}
+
+	return allowed, len(window.hits), window.hits[0], nil
+}
+
+// sweep drops idle windows at most once a minute so unused keys do not pile up
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+
+	for key, window := range s.windows {
+		window.prune(now)
+		if len(window.hits) == 0 {
+			delete(s.windows, key)
+		}
+	}
+}
+
+func (w *memoryWindow) prune(now time.Time) {
+	start := now.Add(-w.length)
+	for len(w.hits) > 0 && !w.hits[0].After(start) {
+		w.hits = w.hits[1:]
+	}
+}
@@ -0,0 +1,77 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/utils/ratelimit"
+)
+
+type failingStore struct{}
+
+func (s failingStore) Hit(ctx context.Context, key string, limit ratelimit.Limit) (bool, int, time.Time, error) {
+	return false, 0, time.Time{}, errors.New("store down")
+}
+
+func TestLimiter(t *testing.T) {
+	Convey("Test Limiter", t, func() {
+		ctx := context.Background()
+		limit := ratelimit.Limit{Requests: 2, Window: time.Minute}
+
+		Convey("Allows hits up to the limit", func() {
+			limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore())
+
+			result := limiter.Allow(ctx, "client:1", limit)
+			So(result.Allowed, ShouldBeTrue)
+			So(result.Remaining, ShouldEqual, 1)
+
+			result = limiter.Allow(ctx, "client:1", limit)
+			So(result.Allowed, ShouldBeTrue)
+			So(result.Remaining, ShouldEqual, 0)
+
+			result = limiter.Allow(ctx, "client:1", limit)
+			So(result.Allowed, ShouldBeFalse)
+			So(result.Reset, ShouldBeGreaterThan, 0)
+			So(result.Reset, ShouldBeLessThanOrEqualTo, time.Minute)
+
+			result = limiter.Allow(ctx, "client:2", limit)
+			So(result.Allowed, ShouldBeTrue)
+		})
+
+		Convey("Frees the window as hits expire", func() {
+			limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore())
+			limit := ratelimit.Limit{Requests: 1, Window: 10 * time.Millisecond}
+
+			So(limiter.Allow(ctx, "client:1", limit).Allowed, ShouldBeTrue)
+			So(limiter.Allow(ctx, "client:1", limit).Allowed, ShouldBeFalse)
+
+			time.Sleep(15 * time.Millisecond)
+			So(limiter.Allow(ctx, "client:1", limit).Allowed, ShouldBeTrue)
+		})
+
+		Convey("Falls back to memory when the store fails", func() {
+			limiter := ratelimit.NewLimiter(failingStore{})
+
+			So(limiter.Allow(ctx, "client:1", limit).Allowed, ShouldBeTrue)
+			So(limiter.Allow(ctx, "client:1", limit).Allowed, ShouldBeTrue)
+			So(limiter.Allow(ctx, "client:1", limit).Allowed, ShouldBeFalse)
+		})
+	})
+}
+
+func TestParseLimit(t *testing.T) {
+	Convey("Test Parse Limit", t, func() {
+		limit, err := ratelimit.ParseLimit("100/1m")
+		So(err, ShouldBeNil)
+		So(limit, ShouldResemble, ratelimit.Limit{Requests: 100, Window: time.Minute})
+
+		for _, value := range []string{"", "100", "0/1m", "abc/1m", "100/abc", "100/-1s"} {
+			_, err := ratelimit.ParseLimit(value)
+			So(err, ShouldNotBeNil)
+		}
+	})
+}
@@ -2,34 +2,172 @@ package redis
 
 import (
 	"context"
-	"os"
+	"errors"
+	"fmt"
+	"time"
 
 	redis "github.com/redis/go-redis/v9"
 )
 
-var dbClient *redis.Client
+// Nil is returned by Get when the key does not exist
+const Nil = redis.Nil
+
+// Config is the connection of a Redis server
+type Config struct {
+	Host     string `env:"CACHE_HOST" validate:"required" description:"server host"`
+	Port     string `env:"CACHE_PORT" default:"6379" description:"server port"`
+	Password string `env:"CACHE_PASSWORD" secret:"true" description:"server password"`
+}
 
-func InitDatabase() {
-	addr := os.Getenv("CACHE_HOST")
-	port := os.Getenv("CACHE_PORT")
-	pass := os.Getenv("CACHE_PASSWORD")
-	if port == "" {
-		port = "6379"
+func (c Config) options() *redis.Options {
+	return &redis.Options{
+		Addr:     c.Host + ":" + c.Port,
+		Password: c.Password,
 	}
-	dbClient = redis.NewClient(&redis.Options{
-		Addr:     addr + ":" + port,
-		Password: pass,
-	})
+}
+
+var dbClient *redis.Client
+
+func InitDatabase(cfg Config) {
+	dbClient = redis.NewClient(cfg.options())
 }
 
 func Set(ctx context.Context, key string, value interface{}) error {
 	return dbClient.Set(ctx, key, value, 24*60*60*1e9).Err() // 1 day in nanoseconds
 }
 
+func SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return dbClient.Set(ctx, key, value, ttl).Err()
+}
+
 func Get(ctx context.Context, key string) (string, error) {
 	return dbClient.Get(ctx, key).Result()
 }
 
-func Del(ctx context.Context, key string) error {
-	return dbClient.Del(ctx, key).Err()
+func Del(ctx context.Context, keys ...string) error {
+	return dbClient.Del(ctx, keys...).Err()
+}
+
+// Incr increments the counter at key and returns its new value
+func Incr(ctx context.Context, key string) (int64, error) {
+	return dbClient.Incr(ctx, key).Result()
+}
+
+// GetInt returns the counter at key, 0 when it does not exist
+func GetInt(ctx context.Context, key string) (int64, error) {
+	value, err := dbClient.Get(ctx, key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return value, err
+}
+
+// setIndexedIfScript stores a value and adds its key to an index set, both expiring
+// after the TTL, only while the counter at the version key still holds the version
+var setIndexedIfScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1]) or '0'
+if current ~= ARGV[1] then
+	return 0
+end
+redis.call('SET', KEYS[2], ARGV[2], 'PX', ARGV[3])
+redis.call('SADD', KEYS[3], KEYS[2])
+redis.call('PEXPIRE', KEYS[3], ARGV[3])
+return 1
+`)
+
+// SetIndexedIf sets key to value and adds key to the set at indexKey in one step, unless
+// the counter at versionKey moved past version. It reports whether the value was stored.
+func SetIndexedIf(ctx context.Context, versionKey string, version int64, key string, value string, indexKey string, ttl time.Duration) (bool, error) {
+	stored, err := setIndexedIfScript.Run(ctx, dbClient, []string{versionKey, key, indexKey}, version, value, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	return stored == 1, nil
+}
+
+func SetMembers(ctx context.Context, key string) ([]string, error) {
+	return dbClient.SMembers(ctx, key).Result()
+}
+
+// Broadcast publishes message on channel over the cache connection
+func Broadcast(ctx context.Context, channel string, message string) error {
+	return dbClient.Publish(ctx, channel, message).Err()
+}
+
+// Listen calls handler for every message broadcast on channel until ctx is done
+func Listen(ctx context.Context, channel string, handler func(string)) error {
+	pubsub := dbClient.Subscribe(ctx, channel)
+	defer pubsub.Close()
+
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			handler(msg.Payload)
+		}
+	}
+}
+
+// Ping checks that the cache answers
+func Ping(ctx context.Context) error {
+	if dbClient == nil {
+		return errors.New("cache is not initialized")
+	}
+	return dbClient.Ping(ctx).Err()
+}
+
+// Close closes the cache connection
+func Close() error {
+	if dbClient == nil {
+		return nil
+	}
+	return dbClient.Close()
+}
+
+// slidingWindowScript records a hit in a sorted set of hit timestamps when the window
+// still has room and returns whether it was allowed, the hits in the window and the oldest hit
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', key, window)
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] == nil then
+	return {allowed, count, now}
+end
+return {allowed, count, tonumber(oldest[2])}
+`)
+
+// SlidingWindow records a hit for key in a sliding window of the given length holding at
+// most limit hits. It returns whether the hit was allowed, the hits in the window and the
+// time of the oldest hit.
+func SlidingWindow(ctx context.Context, key string, limit int, window time.Duration, member string) (bool, int, time.Time, error) {
+	now := time.Now().UnixMilli()
+	values, err := slidingWindowScript.Run(ctx, dbClient, []string{key}, now, window.Milliseconds(), limit, member).Int64Slice()
+	if err != nil {
+		return false, 0, time.Time{}, err
+	}
+	if len(values) != 3 {
+		return false, 0, time.Time{}, fmt.Errorf("unexpected sliding window result %v", values)
+	}
+
+	return values[0] == 1, int(values[1]), time.UnixMilli(values[2]), nil
 }
@@ -2,24 +2,15 @@ package redis
 
 import (
 	"context"
-	"os"
 
 	redis "github.com/redis/go-redis/v9"
 )
 
 var pubsubClient *redis.Client
 
-func InitPubsub() {
-	addr := os.Getenv("MESSAGE_HOST")
-	port := os.Getenv("MESSAGE_PORT")
-	pass := os.Getenv("MESSAGE_PASSWORD")
-	if port == "" {
-		port = "6379"
-	}
-	pubsubClient = redis.NewClient(&redis.Options{
-		Addr:     addr + ":" + port,
-		Password: pass,
-	})
+// InitPubsub connects the Redis server used as a message broker
+func InitPubsub(cfg Config) {
+	pubsubClient = redis.NewClient(cfg.options())
 }
 
 func Publish(ctx context.Context, channel string, message string) error {
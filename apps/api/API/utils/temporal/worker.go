@@ -3,7 +3,6 @@ package temporal
 import (
 	"context"
 	"fmt"
-	"os"
 	"time"
 
 	"go.temporal.io/api/workflowservice/v1"
@@ -19,36 +18,37 @@ type WorkerConfig struct {
 	TaskQueueActivitiesPerSecond float64
 }
 
-func NewWorker(ctx context.Context, name string) (worker.Worker, error) {
-	hostPort := fmt.Sprintf("%s:%s", os.Getenv("WORKFLOW_HOST"), os.Getenv("WORKFLOW_PORT"))
-	namespace := getNamespace()
-
-	// Ensure namespace exists with proper error handling
-	err := ensureNamespaceExists(ctx, hostPort, namespace)
+// NewWorker creates a worker of the task queue name on the shared client. Stopping it
+// waits up to stopTimeout for running activities.
+func NewWorker(ctx context.Context, name string, stopTimeout time.Duration) (worker.Worker, error) {
+	c, err := getClient(ctx, getNamespace())
 	if err != nil {
-		return nil, fmt.Errorf("failed to ensure namespace exists: %w", err)
+		return nil, err
 	}
 
-	c, err := client.Dial(client.Options{
-		HostPort:  hostPort,
-		Namespace: namespace,
+	w := worker.New(c, name, worker.Options{
+		WorkerStopTimeout: stopTimeout,
 	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to dial temporal client: %w", err)
-	}
-
-	w := worker.New(c, name, worker.Options{})
 
 	return w, nil
 }
 
-// getNamespace returns the namespace with fallback to default
+// InterruptCh returns a channel for worker.Run that is closed when ctx is done
+func InterruptCh(ctx context.Context) <-chan interface{} {
+	ch := make(chan interface{})
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return ch
+}
+
+// getNamespace returns the namespace the workers poll
 func getNamespace() string {
-	namespace := os.Getenv("WORKFLOW_NAMESPACE")
-	if namespace == "" {
-		namespace = "default" // Fallback to default namespace
-	}
-	return namespace
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+
+	return settings.Namespace
 }
 
 // ensureNamespaceExists creates namespace if it doesn't exist
@@ -3,14 +3,44 @@ package temporal
 import (
 	"context"
 	"fmt"
-	"os"
+	"sync"
 
 	"github.com/pborman/uuid"
 	"go.temporal.io/sdk/client"
 )
 
-func ExecuteWorkflow(ctx context.Context, namespace, name string, input interface{}) (client.WorkflowRun, error) {
-	hostPort := fmt.Sprintf("%s:%s", os.Getenv("WORKFLOW_HOST"), os.Getenv("WORKFLOW_PORT"))
+// Config is the connection of the workflow service
+type Config struct {
+	Host string `env:"WORKFLOW_HOST" validate:"required" description:"frontend host"`
+	Port string `env:"WORKFLOW_PORT" default:"7233" description:"frontend port"`
+	// Namespace is created when missing
+	Namespace string `env:"WORKFLOW_NAMESPACE" default:"default" description:"namespace, created when missing"`
+}
+
+var (
+	clientsMu sync.Mutex
+	clients   = map[string]client.Client{}
+	settings  = Config{Namespace: "default"}
+)
+
+// Init sets the service the shared clients dial, before any of them is used
+func Init(cfg Config) {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+
+	settings = cfg
+}
+
+// getClient returns the shared client of namespace, dialing it on first use
+func getClient(ctx context.Context, namespace string) (client.Client, error) {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+
+	if c, ok := clients[namespace]; ok {
+		return c, nil
+	}
+
+	hostPort := fmt.Sprintf("%s:%s", settings.Host, settings.Port)
 
 	// Ensure namespace exists with proper error handling
 	err := ensureNamespaceExists(ctx, hostPort, namespace)
@@ -18,7 +48,7 @@ func ExecuteWorkflow(ctx context.Context, namespace, name string, input interfac
 		return nil, fmt.Errorf("failed to ensure namespace exists: %w", err)
 	}
 
-	c, err := client.Dial(client.Options{
+	c, err := client.DialContext(ctx, client.Options{
 		HostPort:  hostPort,
 		Namespace: namespace,
 	})
@@ -26,6 +56,38 @@ func ExecuteWorkflow(ctx context.Context, namespace, name string, input interfac
 		return nil, fmt.Errorf("failed to dial temporal client: %w", err)
 	}
 
+	clients[namespace] = c
+	return c, nil
+}
+
+// Ping checks the health of the workflow service through the shared client of namespace
+func Ping(ctx context.Context, namespace string) error {
+	c, err := getClient(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.CheckHealth(ctx, &client.CheckHealthRequest{})
+	return err
+}
+
+// Close closes the shared clients, workers using them must be stopped first
+func Close() {
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+
+	for namespace, c := range clients {
+		c.Close()
+		delete(clients, namespace)
+	}
+}
+
+func ExecuteWorkflow(ctx context.Context, namespace, name string, input interface{}) (client.WorkflowRun, error) {
+	c, err := getClient(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
 	workflowOptions := client.StartWorkflowOptions{
 		ID:        name + uuid.New(),
 		TaskQueue: name,
@@ -95,19 +95,3 @@ func IsInList(list []string, s string) bool {
 	}
 	return false
 }
-
-func GetDatabaseString() string {
-	return fmt.Sprintf("%s://%s:%s@%s:%s/%s?sslmode=%s&connect_timeout=5",
-		os.Getenv("OUTBOUND_DATABASE_DRIVER"),
-		os.Getenv("DATABASE_USERNAME"),
-		os.Getenv("DATABASE_PASSWORD"),
-		os.Getenv("DATABASE_HOST"),
-		os.Getenv("DATABASE_PORT"),
-		os.Getenv("DATABASE_NAME"),
-		os.Getenv("DATABASE_SSLMODE"),
-	)
-}
-
-func GetMigrationDir() string {
-	return fmt.Sprintf("./internal/migration/%s", os.Getenv("OUTBOUND_DB_DRIVER"))
-}
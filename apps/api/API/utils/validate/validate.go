@@ -0,0 +1,204 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Rules are read from the validate struct tag, separated by commas:
+//
+//	required  the value is not zero, strings are not blank
+//	min=N     strings have at least N characters, slices N items, numbers are at least N
+//	max=N     strings have at most N characters, slices N items, numbers are at most N
+//	server    the field is set by the server, Strip zeroes it in caller input
+//
+// On a time.Duration, N is a duration with its unit, e.g. min=100ms. A bare number
+// other than 0 panics there, as it would count nanoseconds.
+//
+// Nested structs and slices are checked too, embedded structs share the path of their parent.
+const tagName = "validate"
+
+// FieldError describes a field that failed one of its rules
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Errors lists the failed fields of a value
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldError := range e {
+		messages[i] = fieldError.Field + " " + fieldError.Message
+	}
+	return strings.Join(messages, ", ")
+}
+
+type rule struct {
+	name  string
+	param string
+}
+
+// Struct checks v, a struct, a pointer to one or a slice of them, against the rules of
+// its fields and returns Errors with the first failed rule of every field
+func Struct(v any) error {
+	var errs Errors
+	walk(reflect.ValueOf(v), "", func(field reflect.StructField, value reflect.Value, path string) {
+		for _, rule := range parseRules(field) {
+			if message := rule.check(value); message != "" {
+				errs = append(errs, FieldError{Field: path, Rule: rule.name, Message: message})
+				return
+			}
+		}
+	})
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Strip zeroes the fields tagged server in v, which must be a pointer or a slice
+func Strip(v any) {
+	walk(reflect.ValueOf(v), "", func(field reflect.StructField, value reflect.Value, path string) {
+		for _, rule := range parseRules(field) {
+			if rule.name == "server" && value.CanSet() {
+				value.SetZero()
+			}
+		}
+	})
+}
+
+func walk(value reflect.Value, path string, visit func(field reflect.StructField, value reflect.Value, path string)) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			walk(value.Index(i), fmt.Sprintf("%s[%d]", path, i), visit)
+		}
+	case reflect.Struct:
+		valueType := value.Type()
+		for i := 0; i < valueType.NumField(); i++ {
+			field := valueType.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			if field.Anonymous {
+				walk(value.Field(i), path, visit)
+				continue
+			}
+
+			fieldPath := fieldName(field)
+			if path != "" {
+				fieldPath = path + "." + fieldPath
+			}
+			visit(field, value.Field(i), fieldPath)
+			walk(value.Field(i), fieldPath, visit)
+		}
+	}
+}
+
+// fieldName returns the JSON name of field, as callers know it
+func fieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+func parseRules(field reflect.StructField) []rule {
+	tag := field.Tag.Get(tagName)
+	if tag == "" {
+		return nil
+	}
+
+	var rules []rule
+	for _, part := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch name {
+		case "required", "min", "max", "server":
+		default:
+			panic(fmt.Sprintf("validate: unknown rule %q on field %s", name, field.Name))
+		}
+		rules = append(rules, rule{name: name, param: param})
+	}
+	return rules
+}
+
+// check returns why value fails the rule, or an empty string
+func (r rule) check(value reflect.Value) string {
+	switch r.name {
+	case "required":
+		if value.Kind() == reflect.String && strings.TrimSpace(value.String()) == "" || value.IsZero() {
+			return "is required"
+		}
+	case "min", "max":
+		if value.Type() == durationType {
+			return r.checkDuration(time.Duration(value.Int()))
+		}
+
+		limit, err := strconv.ParseFloat(r.param, 64)
+		if err != nil {
+			panic(fmt.Sprintf("validate: invalid %s=%s", r.name, r.param))
+		}
+
+		size, unit := measure(value)
+		if r.name == "min" && size < limit {
+			return fmt.Sprintf("must be at least %s%s", r.param, unit)
+		}
+		if r.name == "max" && size > limit {
+			return fmt.Sprintf("must be at most %s%s", r.param, unit)
+		}
+	}
+
+	return ""
+}
+
+// checkDuration returns why duration fails the min or max rule, or an empty string
+func (r rule) checkDuration(duration time.Duration) string {
+	limit, err := time.ParseDuration(r.param)
+	if err != nil {
+		panic(fmt.Sprintf("validate: invalid %s=%s on a duration, it needs a unit", r.name, r.param))
+	}
+
+	if r.name == "min" && duration < limit {
+		return fmt.Sprintf("must be at least %s", r.param)
+	}
+	if r.name == "max" && duration > limit {
+		return fmt.Sprintf("must be at most %s", r.param)
+	}
+	return ""
+}
+
+// measure returns the length of strings and collections, and the value of numbers
+func measure(value reflect.Value) (float64, string) {
+	switch value.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), " items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), ""
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), ""
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), ""
+	}
+	return 0, ""
+}
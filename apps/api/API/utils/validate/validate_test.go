@@ -0,0 +1,95 @@
+package validate_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"prabogo/utils/validate"
+)
+
+type item struct {
+	Name      string    `json:"name" validate:"required,max=5"`
+	Tags      []string  `json:"tags" validate:"max=2"`
+	Count     int       `json:"count" validate:"min=1"`
+	CreatedAt time.Time `json:"created_at" validate:"server"`
+}
+
+type order struct {
+	Items []item `json:"items" validate:"required"`
+}
+
+func TestStruct(t *testing.T) {
+	Convey("Test Struct", t, func() {
+		Convey("Valid value", func() {
+			So(validate.Struct(item{Name: "kost", Count: 1}), ShouldBeNil)
+		})
+
+		Convey("Reports the first failed rule of every field", func() {
+			err := validate.Struct(item{Name: "  ", Tags: []string{"a", "b", "c"}})
+			So(err, ShouldResemble, validate.Errors{
+				{Field: "name", Rule: "required", Message: "is required"},
+				{Field: "tags", Rule: "max", Message: "must be at most 2 items"},
+				{Field: "count", Rule: "min", Message: "must be at least 1"},
+			})
+			So(err.Error(), ShouldStartWith, "name is required, tags must be at most 2 items")
+		})
+
+		Convey("Counts characters, not bytes", func() {
+			So(validate.Struct(item{Name: "kösté", Count: 1}), ShouldBeNil)
+			So(validate.Struct(item{Name: strings.Repeat("a", 6), Count: 1}), ShouldNotBeNil)
+		})
+
+		Convey("Names nested fields by their path", func() {
+			err := validate.Struct(&order{Items: []item{{Name: "kost", Count: 1}, {Count: 1}}})
+			So(err, ShouldResemble, validate.Errors{
+				{Field: "items[1].name", Rule: "required", Message: "is required"},
+			})
+
+			err = validate.Struct([]order{{}})
+			So(err, ShouldResemble, validate.Errors{
+				{Field: "[0].items", Rule: "required", Message: "is required"},
+			})
+		})
+
+		Convey("Reads duration limits with their unit", func() {
+			type poll struct {
+				Interval time.Duration `json:"interval" validate:"min=100ms,max=1m"`
+			}
+			So(validate.Struct(poll{Interval: time.Second}), ShouldBeNil)
+			So(validate.Struct(poll{Interval: time.Millisecond}), ShouldResemble, validate.Errors{
+				{Field: "interval", Rule: "min", Message: "must be at least 100ms"},
+			})
+			So(validate.Struct(poll{Interval: time.Hour}), ShouldResemble, validate.Errors{
+				{Field: "interval", Rule: "max", Message: "must be at most 1m"},
+			})
+
+			type nanoseconds struct {
+				Interval time.Duration `validate:"min=1"`
+			}
+			So(func() { validate.Struct(nanoseconds{}) }, ShouldPanic)
+		})
+
+		Convey("Panics on an unknown rule", func() {
+			type invalid struct {
+				Name string `validate:"email"`
+			}
+			So(func() { validate.Struct(invalid{}) }, ShouldPanic)
+		})
+	})
+}
+
+func TestStrip(t *testing.T) {
+	Convey("Test Strip", t, func() {
+		items := []item{{Name: "kost", CreatedAt: time.Now()}}
+		validate.Strip(items)
+		So(items[0].CreatedAt.IsZero(), ShouldBeTrue)
+		So(items[0].Name, ShouldEqual, "kost")
+
+		single := item{Name: "kost", CreatedAt: time.Now()}
+		validate.Strip(&single)
+		So(single.CreatedAt.IsZero(), ShouldBeTrue)
+	})
+}